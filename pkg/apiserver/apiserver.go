@@ -2,12 +2,22 @@ package apiserver
 
 import (
 	"context"
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/constants"
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/controller/migration"
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/controller/rollback"
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/controller/rollout"
 	"github.com/Gentleelephant/EnhancementWorkload/pkg/informers"
 	"github.com/Gentleelephant/EnhancementWorkload/pkg/kapis/v1alpha1"
 	restfulspec "github.com/emicklei/go-restful-openapi/v2"
 	"github.com/emicklei/go-restful/v3"
 	kruisev1alpha1 "github.com/openkruise/kruise-api/apps/v1alpha1"
+	kruisev1beta1 "github.com/openkruise/kruise-api/apps/v1beta1"
 	kruiseclientset "github.com/openkruise/kruise-api/client/clientset/versioned"
+	kruisepolicyv1alpha1 "github.com/openkruise/kruise-api/policy/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
@@ -45,7 +55,7 @@ func (s *APIServer) PrepareRun(stopCh <-chan struct{}) error {
 	cors := restful.CrossOriginResourceSharing{
 		ExposeHeaders:  []string{"X-My-Header"},
 		AllowedHeaders: []string{"Content-Type", "Accept"},
-		AllowedMethods: []string{http.MethodGet, http.MethodPost, http.MethodDelete, http.MethodPut},
+		AllowedMethods: []string{http.MethodGet, http.MethodPost, http.MethodDelete, http.MethodPut, http.MethodPatch},
 		CookiesAllowed: false,
 		Container:      s.Container}
 	s.Container.Filter(cors.Filter)
@@ -104,12 +114,16 @@ func (s *APIServer) waitForResourceSync(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	rolloutController := rollout.NewController(s.KruiseClient, informerFactory.KruiseInformerFactory())
+	rollbackController := rollback.NewController(s.KruiseClient, s.K8sclient, informerFactory.KruiseInformerFactory(), informerFactory.KubernetesSharedInformerFactory())
 	cloneSetInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
-
+			rolloutController.Enqueue(obj)
+			rollbackController.EnqueueCloneSet(obj)
 		},
 		UpdateFunc: func(oldObj, newObj interface{}) {
-
+			rolloutController.Enqueue(newObj)
+			rollbackController.EnqueueCloneSet(newObj)
 		},
 		DeleteFunc: func(obj interface{}) {
 
@@ -131,9 +145,72 @@ func (s *APIServer) waitForResourceSync(ctx context.Context) error {
 		},
 	})
 
+	for _, gvr := range []schema.GroupVersionResource{
+		kruisev1beta1.SchemeGroupVersion.WithResource(constants.StatefulSetType),
+		kruisev1alpha1.SchemeGroupVersion.WithResource(constants.DaemonSetType),
+		kruisev1alpha1.SchemeGroupVersion.WithResource(constants.BroadcastJobType),
+		kruisev1alpha1.SchemeGroupVersion.WithResource(constants.AdvancedCronJobType),
+		kruisev1alpha1.SchemeGroupVersion.WithResource(constants.UnitedDeploymentType),
+		kruisev1alpha1.SchemeGroupVersion.WithResource(constants.WorkloadSpreadType),
+		kruisev1alpha1.SchemeGroupVersion.WithResource(constants.ContainerRecreateRequestType),
+		kruisev1alpha1.SchemeGroupVersion.WithResource(constants.ImagePullJobType),
+		kruisev1alpha1.SchemeGroupVersion.WithResource(constants.NodeImageType),
+		kruisev1alpha1.SchemeGroupVersion.WithResource(constants.ResourceDistributionType),
+		kruisev1alpha1.SchemeGroupVersion.WithResource(constants.PodProbeMarkerType),
+		kruisev1alpha1.SchemeGroupVersion.WithResource(constants.NodePodProbeType),
+		kruisev1alpha1.SchemeGroupVersion.WithResource(constants.PersistentPodStateType),
+		kruisev1alpha1.SchemeGroupVersion.WithResource(constants.EphemeralJobType),
+		kruisepolicyv1alpha1.SchemeGroupVersion.WithResource(constants.PodUnavailableBudgetType),
+	} {
+		if _, err = informerFactory.KruiseInformerFactory().ForResource(gvr); err != nil {
+			return err
+		}
+	}
+
+	podInformer, err := informerFactory.KubernetesSharedInformerFactory().ForResource(corev1.SchemeGroupVersion.WithResource(constants.PodType))
+	if err != nil {
+		return err
+	}
+	podInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			rollbackController.EnqueuePod(obj)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			rollbackController.EnqueuePod(newObj)
+		},
+	})
+
+	migrationController := migration.NewController(s.KruiseClient, s.K8sclient, informerFactory.KruiseInformerFactory(), informerFactory.KubernetesSharedInformerFactory())
+	deploymentInformer, err := informerFactory.KubernetesSharedInformerFactory().ForResource(appsv1.SchemeGroupVersion.WithResource(constants.DeploymentType))
+	if err != nil {
+		return err
+	}
+	deploymentInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			migrationController.Enqueue(obj)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			migrationController.Enqueue(newObj)
+		},
+	})
+
+	for _, gvr := range []schema.GroupVersionResource{
+		appsv1.SchemeGroupVersion.WithResource(constants.StatefulSetType),
+		corev1.SchemeGroupVersion.WithResource(constants.EventType),
+		batchv1.SchemeGroupVersion.WithResource(constants.JobType),
+	} {
+		if _, err = informerFactory.KubernetesSharedInformerFactory().ForResource(gvr); err != nil {
+			return err
+		}
+	}
+
 	s.InformerFactory.Start(stopCh)
 	s.InformerFactory.WaitForCacheSync(stopCh)
 
+	go rolloutController.Run(ctx, 1)
+	go rollbackController.Run(ctx, 1)
+	go migrationController.Run(ctx, 1)
+
 	klog.V(0).Info("Finished caching objects")
 	return nil
 }
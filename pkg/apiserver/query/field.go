@@ -33,6 +33,7 @@ const (
 	FieldStatus              = "status"
 	FieldOwnerReference      = "ownerReference"
 	FieldOwnerKind           = "ownerKind"
+	FieldPodName             = "podName"
 
 	FieldType = "type"
 )
@@ -21,15 +21,98 @@ const (
 
 	SidecarSetTag = "SidecarSet"
 
+	StatefulSetTag = "StatefulSet"
+
+	DaemonSetTag = "DaemonSet"
+
+	BroadcastJobTag = "BroadcastJob"
+
+	AdvancedCronJobTag = "AdvancedCronJob"
+
+	UnitedDeploymentTag = "UnitedDeployment"
+
+	WorkloadSpreadTag = "WorkloadSpread"
+
+	PodUnavailableBudgetTag = "PodUnavailableBudget"
+
+	ContainerRecreateRequestTag = "ContainerRecreateRequest"
+
+	ImagePullJobTag = "ImagePullJob"
+
+	NodeImageTag = "NodeImage"
+
+	ResourceDistributionTag = "ResourceDistribution"
+
+	PodProbeMarkerTag = "PodProbeMarker"
+
+	NodePodProbeTag = "NodePodProbe"
+
+	PersistentPodStateTag = "PersistentPodState"
+
+	EphemeralJobTag = "EphemeralJob"
+
+	DeploymentTag = "Deployment"
+
 	CloneSetType = "clonesets"
 
 	PodType = "pods"
 
+	EventType = "events"
+
+	JobType = "jobs"
+
 	SidecarSetType = "sidecarsets"
 
+	StatefulSetType = "statefulsets"
+
+	DaemonSetType = "daemonsets"
+
+	BroadcastJobType = "broadcastjobs"
+
+	AdvancedCronJobType = "advancedcronjobs"
+
+	UnitedDeploymentType = "uniteddeployments"
+
+	WorkloadSpreadType = "workloadspreads"
+
+	PodUnavailableBudgetType = "podunavailablebudgets"
+
+	ContainerRecreateRequestType = "containerrecreaterequests"
+
+	ImagePullJobType = "imagepulljobs"
+
+	NodeImageType = "nodeimages"
+
+	ResourceDistributionType = "resourcedistributions"
+
+	PodProbeMarkerType = "podprobemarkers"
+
+	NodePodProbeType = "nodepodprobes"
+
+	PersistentPodStateType = "persistentpodstates"
+
+	EphemeralJobType = "ephemeraljobs"
+
+	DeploymentType = "deployments"
+
 	Common = "common"
 
 	UserAgent = "X-KS-User"
+
+	// RolloutPlanAnnotation holds the progressive rollout plan of a CloneSet.
+	RolloutPlanAnnotation = "workload.kubesphere.io/rollout-plan"
+
+	// RolloutStatusAnnotation holds the progress of the rollout plan of a CloneSet.
+	RolloutStatusAnnotation = "workload.kubesphere.io/rollout-status"
+
+	// RollbackPolicyAnnotation holds the automatic rollback policy of a CloneSet.
+	RollbackPolicyAnnotation = "workload.kubesphere.io/rollback-policy"
+
+	// RollbackStatusAnnotation holds the watched rollout and the last automatic rollback of a CloneSet.
+	RollbackStatusAnnotation = "workload.kubesphere.io/rollback-status"
+
+	// DeploymentMigrationAnnotation holds the progress of the migration of a Deployment to a CloneSet.
+	DeploymentMigrationAnnotation = "workload.kubesphere.io/migration"
 )
 
 // ContextKeyK8SToken represents a type alias for the context key
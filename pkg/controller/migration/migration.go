@@ -0,0 +1,284 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/constants"
+	modelsv1alpha1 "github.com/Gentleelephant/EnhancementWorkload/pkg/models/v1alpha1"
+	"github.com/openkruise/kruise-api/apps/v1alpha1"
+	kruiseclientset "github.com/openkruise/kruise-api/client/clientset/versioned"
+	kruiseinformer "github.com/openkruise/kruise-api/client/informers/externalversions"
+	kruiselisters "github.com/openkruise/kruise-api/client/listers/apps/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	k8sinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+// pollInterval is how often the readiness of the pods added to the CloneSet is checked.
+const pollInterval = 2 * time.Second
+
+// Controller moves the replicas of Deployments migrated with ScaleDown to their CloneSets. Each step
+// scales the CloneSet up, waits for the added pods to be ready and scales the Deployment down by the
+// same amount. The progress is kept in the migration status of the Deployment, so a migration carries
+// on after the server restarts.
+type Controller struct {
+	kruiseclientset     kruiseclientset.Interface
+	kubernetesclientset kubernetes.Interface
+	deploymentLister    appslisters.DeploymentLister
+	cloneSetLister      kruiselisters.CloneSetLister
+	queue               workqueue.RateLimitingInterface
+}
+
+func NewController(clientset kruiseclientset.Interface, k8sclient kubernetes.Interface, informer kruiseinformer.SharedInformerFactory, k8sinformer k8sinformers.SharedInformerFactory) *Controller {
+	return &Controller{
+		kruiseclientset:     clientset,
+		kubernetesclientset: k8sclient,
+		deploymentLister:    k8sinformer.Apps().V1().Deployments().Lister(),
+		cloneSetLister:      informer.Apps().V1alpha1().CloneSets().Lister(),
+		queue:               workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "migration"),
+	}
+}
+
+// Enqueue queues a Deployment with a migration status, it is meant to be called from the Deployment informer handlers.
+func (c *Controller) Enqueue(obj interface{}) {
+	deployment, ok := obj.(*appsv1.Deployment)
+	if !ok {
+		return
+	}
+	if _, ok := deployment.Annotations[constants.DeploymentMigrationAnnotation]; !ok {
+		return
+	}
+	key, err := cache.MetaNamespaceKeyFunc(deployment)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.queue.Add(key)
+}
+
+// Run processes queued Deployments until the context is done.
+func (c *Controller) Run(ctx context.Context, workers int) {
+	defer utilruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	klog.V(0).Info("Starting migration controller")
+	for i := 0; i < workers; i++ {
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
+	}
+	<-ctx.Done()
+	klog.V(0).Info("Shutting down migration controller")
+}
+
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextItem(ctx) {
+	}
+}
+
+func (c *Controller) processNextItem(ctx context.Context) bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	requeueAfter, err := c.sync(ctx, key.(string))
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to sync migration of deployment %s: %v", key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	if requeueAfter > 0 {
+		c.queue.AddAfter(key, requeueAfter)
+	}
+	return true
+}
+
+func (c *Controller) sync(ctx context.Context, key string) (time.Duration, error) {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return 0, err
+	}
+	deployment, err := c.deploymentLister.Deployments(namespace).Get(name)
+	if errors.IsNotFound(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	status, err := modelsv1alpha1.GetDeploymentMigrationStatus(deployment)
+	if err != nil || status == nil || !status.InProgress() {
+		// an invalid status is not retried, it is picked up again once the Deployment changes
+		if err != nil {
+			klog.Error(err)
+		}
+		return 0, nil
+	}
+
+	cloneSet, err := c.cloneSetLister.CloneSets(namespace).Get(status.CloneSet)
+	if errors.IsNotFound(err) {
+		// the CloneSet may have been created after the informer last synced
+		cloneSet, err = c.kruiseclientset.AppsV1alpha1().CloneSets(namespace).Get(ctx, status.CloneSet, v1.GetOptions{})
+	}
+	if errors.IsNotFound(err) {
+		cloneSet, err = nil, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	next, cloneSetReplicas, deploymentReplicas, requeueAfter := migrate(cloneSet, *status, time.Now())
+	if cloneSetReplicas != nil {
+		if err = c.scaleCloneSet(ctx, cloneSet, *cloneSetReplicas); err != nil {
+			return 0, err
+		}
+	}
+	if equality.Semantic.DeepEqual(next, *status) && deploymentReplicas == nil {
+		return requeueAfter, nil
+	}
+
+	statusData, err := json.Marshal(next)
+	if err != nil {
+		return 0, err
+	}
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"resourceVersion": deployment.ResourceVersion,
+			"annotations": map[string]interface{}{
+				constants.DeploymentMigrationAnnotation: string(statusData),
+			},
+		},
+	}
+	if deploymentReplicas != nil {
+		patch["spec"] = map[string]interface{}{"replicas": *deploymentReplicas}
+	}
+	patchData, err := json.Marshal(patch)
+	if err != nil {
+		return 0, err
+	}
+	if _, err = c.kubernetesclientset.AppsV1().Deployments(namespace).Patch(ctx, name, types.MergePatchType, patchData, v1.PatchOptions{}); err != nil {
+		return 0, err
+	}
+	if next.Phase != status.Phase {
+		klog.Infof("migration of deployment %s to cloneset %s is %s: %s", key, next.CloneSet, next.Phase, next.Message)
+	}
+	return requeueAfter, nil
+}
+
+func (c *Controller) scaleCloneSet(ctx context.Context, cloneSet *v1alpha1.CloneSet, replicas int32) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{"replicas": replicas},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = c.kruiseclientset.AppsV1alpha1().CloneSets(cloneSet.Namespace).Patch(ctx, cloneSet.Name, types.MergePatchType, patch, v1.PatchOptions{})
+	return err
+}
+
+// migrate computes the next status of the migration, the replicas to set on the CloneSet and on the
+// Deployment, nil to leave them as they are, and how long to wait before checking the migration again
+// when nothing else changes the Deployment. Replicas are absolute, so repeating a step after a failed
+// write is harmless.
+func migrate(cloneSet *v1alpha1.CloneSet, status modelsv1alpha1.DeploymentMigrationStatus, now time.Time) (modelsv1alpha1.DeploymentMigrationStatus, *int32, *int32, time.Duration) {
+	fail := func(message string) (modelsv1alpha1.DeploymentMigrationStatus, *int32, *int32, time.Duration) {
+		completionTime := v1.NewTime(now)
+		status.Phase = modelsv1alpha1.MigrationFailed
+		status.Message = message
+		status.StepStartTime = nil
+		status.CompletionTime = &completionTime
+		return status, nil, nil, 0
+	}
+	if cloneSet == nil {
+		// a pending migration is recorded before its CloneSet is created
+		if status.Phase == modelsv1alpha1.MigrationPending {
+			if remaining := status.StartTime.Add(time.Duration(status.TimeoutSeconds) * time.Second).Sub(now); remaining > 0 {
+				if remaining > pollInterval {
+					remaining = pollInterval
+				}
+				return status, nil, nil, remaining
+			}
+		}
+		return fail(fmt.Sprintf("cloneset %s not found", status.CloneSet))
+	}
+	if !status.ScaleDown {
+		// the request creating the CloneSet did not get to complete the migration, there is nothing to move
+		completionTime := v1.NewTime(now)
+		status.Phase = modelsv1alpha1.MigrationCompleted
+		status.CompletionTime = &completionTime
+		return status, nil, nil, 0
+	}
+	status.Phase = modelsv1alpha1.MigrationMigrating
+
+	if moved := status.Replicas - status.DeploymentReplicas; status.CloneSetReplicas > moved {
+		// the CloneSet was scaled up, the Deployment follows once the added pods are ready
+		if cloneSet.Status.ObservedGeneration >= cloneSet.Generation && cloneSet.Status.ReadyReplicas >= status.CloneSetReplicas {
+			deploymentReplicas := status.Replicas - status.CloneSetReplicas
+			status.DeploymentReplicas = deploymentReplicas
+			status.StepStartTime = nil
+			return status, nil, &deploymentReplicas, 0
+		}
+		timeout := time.Duration(status.TimeoutSeconds) * time.Second
+		if status.StepStartTime == nil {
+			startTime := v1.NewTime(now)
+			status.StepStartTime = &startTime
+		}
+		remaining := status.StepStartTime.Add(timeout).Sub(now)
+		if remaining <= 0 {
+			return fail(fmt.Sprintf("pods of cloneset %s are not ready after %d seconds", status.CloneSet, status.TimeoutSeconds))
+		}
+		if remaining > pollInterval {
+			remaining = pollInterval
+		}
+		return status, nil, nil, remaining
+	}
+
+	if status.CloneSetReplicas >= status.Replicas {
+		completionTime := v1.NewTime(now)
+		status.Phase = modelsv1alpha1.MigrationCompleted
+		status.CompletionTime = &completionTime
+		return status, nil, nil, 0
+	}
+	step := status.StepReplicas
+	if step <= 0 {
+		step = 1
+	}
+	if left := status.Replicas - status.CloneSetReplicas; left < step {
+		step = left
+	}
+	cloneSetReplicas := status.CloneSetReplicas + step
+	startTime := v1.NewTime(now)
+	status.CloneSetReplicas = cloneSetReplicas
+	status.StepStartTime = &startTime
+	return status, &cloneSetReplicas, nil, pollInterval
+}
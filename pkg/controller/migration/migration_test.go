@@ -0,0 +1,155 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"testing"
+	"time"
+
+	modelsv1alpha1 "github.com/Gentleelephant/EnhancementWorkload/pkg/models/v1alpha1"
+	"github.com/openkruise/kruise-api/apps/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newCloneSet(replicas, ready int32) *v1alpha1.CloneSet {
+	return &v1alpha1.CloneSet{
+		ObjectMeta: v1.ObjectMeta{Name: "web", Namespace: "default", Generation: 2},
+		Spec:       v1alpha1.CloneSetSpec{Replicas: &replicas},
+		Status:     v1alpha1.CloneSetStatus{ObservedGeneration: 2, Replicas: replicas, ReadyReplicas: ready},
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *v1.Time {
+		t := v1.NewTime(now.Add(-d))
+		return &t
+	}
+	replicas := func(r int32) *int32 {
+		return &r
+	}
+	migrating := func(phase string, cloneSetReplicas, deploymentReplicas int32, stepStartTime *v1.Time) modelsv1alpha1.DeploymentMigrationStatus {
+		return modelsv1alpha1.DeploymentMigrationStatus{Deployment: "web", CloneSet: "web", Phase: phase, ScaleDown: true, Replicas: 5, StepReplicas: 2, TimeoutSeconds: 60,
+			CloneSetReplicas: cloneSetReplicas, DeploymentReplicas: deploymentReplicas, StepStartTime: stepStartTime, StartTime: *at(30 * time.Second)}
+	}
+	failed := func(status modelsv1alpha1.DeploymentMigrationStatus, message string) modelsv1alpha1.DeploymentMigrationStatus {
+		status.Phase = modelsv1alpha1.MigrationFailed
+		status.Message = message
+		status.StepStartTime = nil
+		status.CompletionTime = at(0)
+		return status
+	}
+	withStartTime := func(status modelsv1alpha1.DeploymentMigrationStatus, startTime *v1.Time) modelsv1alpha1.DeploymentMigrationStatus {
+		status.StartTime = *startTime
+		return status
+	}
+	withoutScaleDown := func(status modelsv1alpha1.DeploymentMigrationStatus) modelsv1alpha1.DeploymentMigrationStatus {
+		status.ScaleDown = false
+		status.StepReplicas = 0
+		return status
+	}
+	completed := func(status modelsv1alpha1.DeploymentMigrationStatus) modelsv1alpha1.DeploymentMigrationStatus {
+		status.Phase = modelsv1alpha1.MigrationCompleted
+		status.CompletionTime = at(0)
+		return status
+	}
+	tests := []struct {
+		name                   string
+		cloneSet               *v1alpha1.CloneSet
+		status                 modelsv1alpha1.DeploymentMigrationStatus
+		wantStatus             modelsv1alpha1.DeploymentMigrationStatus
+		wantCloneSetReplicas   *int32
+		wantDeploymentReplicas *int32
+		wantRequeue            time.Duration
+	}{
+		{
+			name:                 "scales the cloneset up for the first step",
+			cloneSet:             newCloneSet(0, 0),
+			status:               migrating(modelsv1alpha1.MigrationPending, 0, 5, nil),
+			wantStatus:           migrating(modelsv1alpha1.MigrationMigrating, 2, 5, at(0)),
+			wantCloneSetReplicas: replicas(2),
+			wantRequeue:          pollInterval,
+		},
+		{
+			name:        "waits for the added pods",
+			cloneSet:    newCloneSet(2, 1),
+			status:      migrating(modelsv1alpha1.MigrationMigrating, 2, 5, at(30*time.Second)),
+			wantStatus:  migrating(modelsv1alpha1.MigrationMigrating, 2, 5, at(30*time.Second)),
+			wantRequeue: pollInterval,
+		},
+		{
+			name:                   "scales the deployment down once the added pods are ready",
+			cloneSet:               newCloneSet(2, 2),
+			status:                 migrating(modelsv1alpha1.MigrationMigrating, 2, 5, at(30*time.Second)),
+			wantStatus:             migrating(modelsv1alpha1.MigrationMigrating, 2, 3, nil),
+			wantDeploymentReplicas: replicas(3),
+		},
+		{
+			name:                 "moves the replicas left in the last step",
+			cloneSet:             newCloneSet(4, 4),
+			status:               migrating(modelsv1alpha1.MigrationMigrating, 4, 1, nil),
+			wantStatus:           migrating(modelsv1alpha1.MigrationMigrating, 5, 1, at(0)),
+			wantCloneSetReplicas: replicas(5),
+			wantRequeue:          pollInterval,
+		},
+		{
+			name:     "completes when all replicas are moved",
+			cloneSet: newCloneSet(5, 5),
+			status:   migrating(modelsv1alpha1.MigrationMigrating, 5, 0, nil),
+			wantStatus: modelsv1alpha1.DeploymentMigrationStatus{Deployment: "web", CloneSet: "web", Phase: modelsv1alpha1.MigrationCompleted, ScaleDown: true, Replicas: 5, StepReplicas: 2,
+				TimeoutSeconds: 60, CloneSetReplicas: 5, StartTime: *at(30 * time.Second), CompletionTime: at(0)},
+		},
+		{
+			name:       "fails when the added pods are not ready in time",
+			cloneSet:   newCloneSet(2, 1),
+			status:     migrating(modelsv1alpha1.MigrationMigrating, 2, 5, at(time.Minute)),
+			wantStatus: failed(migrating(modelsv1alpha1.MigrationMigrating, 2, 5, nil), "pods of cloneset web are not ready after 60 seconds"),
+		},
+		{
+			name:        "waits for the cloneset of a pending migration",
+			status:      migrating(modelsv1alpha1.MigrationPending, 0, 5, nil),
+			wantStatus:  migrating(modelsv1alpha1.MigrationPending, 0, 5, nil),
+			wantRequeue: pollInterval,
+		},
+		{
+			name:       "fails when the cloneset of a pending migration is not created in time",
+			status:     withStartTime(migrating(modelsv1alpha1.MigrationPending, 0, 5, nil), at(2*time.Minute)),
+			wantStatus: failed(withStartTime(migrating(modelsv1alpha1.MigrationPending, 0, 5, nil), at(2*time.Minute)), "cloneset web not found"),
+		},
+		{
+			name:       "completes a pending migration without scale down once the cloneset exists",
+			cloneSet:   newCloneSet(5, 0),
+			status:     withoutScaleDown(migrating(modelsv1alpha1.MigrationPending, 5, 5, nil)),
+			wantStatus: completed(withoutScaleDown(migrating(modelsv1alpha1.MigrationPending, 5, 5, nil))),
+		},
+		{
+			name:       "fails when the cloneset is gone",
+			status:     migrating(modelsv1alpha1.MigrationMigrating, 2, 3, nil),
+			wantStatus: failed(migrating(modelsv1alpha1.MigrationMigrating, 2, 3, nil), "cloneset web not found"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, cloneSetReplicas, deploymentReplicas, requeue := migrate(tt.cloneSet, tt.status, now)
+			assert.Equal(t, tt.wantStatus, status)
+			assert.Equal(t, tt.wantCloneSetReplicas, cloneSetReplicas)
+			assert.Equal(t, tt.wantDeploymentReplicas, deploymentReplicas)
+			assert.Equal(t, tt.wantRequeue, requeue)
+		})
+	}
+}
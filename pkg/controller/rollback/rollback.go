@@ -0,0 +1,343 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rollback
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/constants"
+	modelsv1alpha1 "github.com/Gentleelephant/EnhancementWorkload/pkg/models/v1alpha1"
+	appspub "github.com/openkruise/kruise-api/apps/pub"
+	"github.com/openkruise/kruise-api/apps/v1alpha1"
+	kruiseclientset "github.com/openkruise/kruise-api/client/clientset/versioned"
+	kruiseinformer "github.com/openkruise/kruise-api/client/informers/externalversions"
+	kruiselisters "github.com/openkruise/kruise-api/client/listers/apps/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	k8sinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+// Controller rolls back CloneSets with a rollback policy when their rollout fails: the updated pods
+// crash-loop, or no more updated pods become ready within the progress deadline. The pod template is
+// restored from the previous ControllerRevision and the reason is recorded in the rollback status.
+type Controller struct {
+	kruiseclientset     kruiseclientset.Interface
+	kubernetesclientset kubernetes.Interface
+	cloneSetLister      kruiselisters.CloneSetLister
+	podLister           corelisters.PodLister
+	queue               workqueue.RateLimitingInterface
+}
+
+func NewController(clientset kruiseclientset.Interface, k8sclient kubernetes.Interface, informer kruiseinformer.SharedInformerFactory, k8sinformer k8sinformers.SharedInformerFactory) *Controller {
+	return &Controller{
+		kruiseclientset:     clientset,
+		kubernetesclientset: k8sclient,
+		cloneSetLister:      informer.Apps().V1alpha1().CloneSets().Lister(),
+		podLister:           k8sinformer.Core().V1().Pods().Lister(),
+		queue:               workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "rollback"),
+	}
+}
+
+// EnqueueCloneSet queues a CloneSet with a rollback policy, it is meant to be called from the CloneSet informer handlers.
+func (c *Controller) EnqueueCloneSet(obj interface{}) {
+	cloneSet, ok := obj.(*v1alpha1.CloneSet)
+	if !ok {
+		return
+	}
+	if _, ok := cloneSet.Annotations[constants.RollbackPolicyAnnotation]; !ok {
+		return
+	}
+	c.queue.Add(types.NamespacedName{Namespace: cloneSet.Namespace, Name: cloneSet.Name}.String())
+}
+
+// EnqueuePod queues the CloneSet controlling the pod, it is meant to be called from the pod informer handlers.
+func (c *Controller) EnqueuePod(obj interface{}) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return
+	}
+	owner := v1.GetControllerOf(pod)
+	if owner == nil || owner.Kind != constants.CloneSetTag || owner.APIVersion != v1alpha1.SchemeGroupVersion.String() {
+		return
+	}
+	cloneSet, err := c.cloneSetLister.CloneSets(pod.Namespace).Get(owner.Name)
+	if err != nil || cloneSet.UID != owner.UID {
+		return
+	}
+	c.EnqueueCloneSet(cloneSet)
+}
+
+// Run processes queued CloneSets until the context is done.
+func (c *Controller) Run(ctx context.Context, workers int) {
+	defer utilruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	klog.V(0).Info("Starting rollback controller")
+	for i := 0; i < workers; i++ {
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
+	}
+	<-ctx.Done()
+	klog.V(0).Info("Shutting down rollback controller")
+}
+
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextItem(ctx) {
+	}
+}
+
+func (c *Controller) processNextItem(ctx context.Context) bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	requeueAfter, err := c.sync(ctx, key.(string))
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to sync rollback of cloneset %s: %v", key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	if requeueAfter > 0 {
+		c.queue.AddAfter(key, requeueAfter)
+	}
+	return true
+}
+
+func (c *Controller) sync(ctx context.Context, key string) (time.Duration, error) {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return 0, err
+	}
+	cloneSet, err := c.cloneSetLister.CloneSets(namespace).Get(name)
+	if errors.IsNotFound(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	policy, status, err := modelsv1alpha1.GetRollbackPolicy(cloneSet)
+	if err != nil || policy == nil {
+		// an invalid policy is not retried, it is picked up again once the CloneSet changes
+		if err != nil {
+			klog.Error(err)
+		}
+		return 0, nil
+	}
+
+	selector, err := v1.LabelSelectorAsSelector(cloneSet.Spec.Selector)
+	if err != nil {
+		return 0, err
+	}
+	selected, err := c.podLister.Pods(namespace).List(selector)
+	if err != nil {
+		return 0, err
+	}
+	pods := make([]*corev1.Pod, 0, len(selected))
+	for _, pod := range selected {
+		if owner := v1.GetControllerOf(pod); owner != nil && owner.UID == cloneSet.UID {
+			pods = append(pods, pod)
+		}
+	}
+
+	next, failure, requeueAfter := evaluate(cloneSet, pods, policy, *status, time.Now())
+	if failure != nil {
+		return 0, c.rollback(ctx, cloneSet, next, failure)
+	}
+	if equality.Semantic.DeepEqual(next, *status) {
+		return requeueAfter, nil
+	}
+	return requeueAfter, c.patchStatus(ctx, cloneSet, next)
+}
+
+// rollback restores the template of the CloneSet from its previous revision, lifting the partition so
+// every pod goes back, and ends the rollout plan of the failed revision.
+func (c *Controller) rollback(ctx context.Context, cloneSet *v1alpha1.CloneSet, status modelsv1alpha1.RollbackStatus, failure *modelsv1alpha1.Rollback) error {
+	revisions, err := modelsv1alpha1.ListCloneSetRevisions(ctx, c.kubernetesclientset, cloneSet)
+	if err != nil {
+		return err
+	}
+	previous := modelsv1alpha1.PreviousRevision(cloneSet, revisions)
+	if previous == nil {
+		klog.Warningf("rollout of cloneset %s/%s failed but there is no previous revision to roll back to: %s", cloneSet.Namespace, cloneSet.Name, failure.Message)
+		return nil
+	}
+
+	failure.ToRevision = previous.Name
+	status.LastRollback = failure
+	statusData, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	_, err = modelsv1alpha1.RollbackCloneSet(ctx, c.kruiseclientset, cloneSet, previous, func(cloneSet *v1alpha1.CloneSet) {
+		cloneSet.Annotations[constants.RollbackStatusAnnotation] = string(statusData)
+		delete(cloneSet.Annotations, constants.RolloutPlanAnnotation)
+		delete(cloneSet.Annotations, constants.RolloutStatusAnnotation)
+		cloneSet.Spec.UpdateStrategy.Partition = nil
+		cloneSet.Spec.UpdateStrategy.Paused = false
+	})
+	if err != nil {
+		return err
+	}
+	klog.Infof("rolled back cloneset %s/%s from revision %s to %s: %s", cloneSet.Namespace, cloneSet.Name, failure.FromRevision, failure.ToRevision, failure.Message)
+	return nil
+}
+
+func (c *Controller) patchStatus(ctx context.Context, cloneSet *v1alpha1.CloneSet, status modelsv1alpha1.RollbackStatus) error {
+	statusData, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"resourceVersion": cloneSet.ResourceVersion,
+			"annotations": map[string]interface{}{
+				constants.RollbackStatusAnnotation: string(statusData),
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = c.kruiseclientset.AppsV1alpha1().CloneSets(cloneSet.Namespace).Patch(ctx, cloneSet.Name, types.MergePatchType, patch, v1.PatchOptions{})
+	return err
+}
+
+// evaluate tracks the progress of the rollout in the status and reports a failure if an updated pod
+// crash-loops or the rollout made no progress within the deadline. It also returns how long to wait
+// before checking the CloneSet again when nothing else changes it.
+func evaluate(cloneSet *v1alpha1.CloneSet, pods []*corev1.Pod, policy *modelsv1alpha1.RollbackPolicy, status modelsv1alpha1.RollbackStatus, now time.Time) (modelsv1alpha1.RollbackStatus, *modelsv1alpha1.Rollback, time.Duration) {
+	if cloneSet.Status.ObservedGeneration < cloneSet.Generation {
+		// wait for the kruise controller to observe the latest spec
+		return status, nil, 0
+	}
+	if cloneSet.Status.CurrentRevision == cloneSet.Status.UpdateRevision {
+		// no rollout in progress, pods that are not ready or restart run the stable revision
+		return status, nil, 0
+	}
+
+	updateRevision := cloneSet.Status.UpdateRevision
+	replicas := int32(1)
+	if cloneSet.Spec.Replicas != nil {
+		replicas = *cloneSet.Spec.Replicas
+	}
+	partition := 0
+	if cloneSet.Spec.UpdateStrategy.Partition != nil {
+		partition, _ = intstr.GetScaledValueFromIntOrPercent(cloneSet.Spec.UpdateStrategy.Partition, int(replicas), true)
+	}
+	target := replicas - int32(partition)
+	if target < 0 {
+		target = 0
+	}
+
+	if status.UpdateRevision != updateRevision || status.TargetReplicas != target ||
+		status.UpdatedReadyReplicas != cloneSet.Status.UpdatedReadyReplicas || status.Paused != cloneSet.Spec.UpdateStrategy.Paused {
+		progressTime := v1.NewTime(now)
+		if status.UpdateRevision != updateRevision {
+			status.RestartBaselines = nil
+		}
+		status.UpdateRevision = updateRevision
+		status.TargetReplicas = target
+		status.UpdatedReadyReplicas = cloneSet.Status.UpdatedReadyReplicas
+		status.Paused = cloneSet.Spec.UpdateStrategy.Paused
+		status.LastProgressTime = &progressTime
+	}
+
+	if status.LastRollback != nil && status.LastRollback.ToRevision == updateRevision {
+		// never roll back the revision restored by the last rollback, that would go back to the failed one
+		return status, nil, 0
+	}
+	if cloneSet.Status.UpdatedReadyReplicas >= target || status.Paused {
+		return status, nil, 0
+	}
+
+	failure := &modelsv1alpha1.Rollback{
+		FromRevision: updateRevision,
+		Time:         v1.NewTime(now),
+	}
+	// only restarts since a pod moved to the update revision count, the baselines of pods that are gone are dropped
+	var baselines map[string]int32
+	for _, pod := range pods {
+		if !modelsv1alpha1.IsPodAtRevision(pod, updateRevision) {
+			continue
+		}
+		for _, containerStatuses := range [][]corev1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+			for _, containerStatus := range containerStatuses {
+				key := pod.Name + "/" + containerStatus.Name
+				baseline, ok := status.RestartBaselines[key]
+				if !ok {
+					baseline = restartBaseline(pod, containerStatus)
+				}
+				if baselines == nil {
+					baselines = map[string]int32{}
+				}
+				baselines[key] = baseline
+				if restarts := containerStatus.RestartCount - baseline; restarts >= policy.MaxRestarts && failure.Reason == "" {
+					failure.Reason = modelsv1alpha1.RollbackReasonCrashLoop
+					failure.Message = fmt.Sprintf("container %s of pod %s restarted %d times since the update", containerStatus.Name, pod.Name, restarts)
+				}
+			}
+		}
+	}
+	status.RestartBaselines = baselines
+	if failure.Reason != "" {
+		return status, failure, 0
+	}
+
+	deadline := status.LastProgressTime.Add(time.Duration(policy.ProgressDeadlineSeconds) * time.Second)
+	if remaining := deadline.Sub(now); remaining > 0 {
+		return status, nil, remaining
+	}
+	failure.Reason = modelsv1alpha1.RollbackReasonProgressDeadline
+	failure.Message = fmt.Sprintf("%d of %d updated pods are ready, no progress for %d seconds", cloneSet.Status.UpdatedReadyReplicas, target, policy.ProgressDeadlineSeconds)
+	return status, failure, 0
+}
+
+// restartBaseline is the restart count of a container when its pod moved to the update revision. Pods
+// created at the update revision start from zero. Pods updated in place keep the restarts from before
+// the update, plus the restart of the update itself while the container still runs the old image.
+func restartBaseline(pod *corev1.Pod, containerStatus corev1.ContainerStatus) int32 {
+	stateData, ok := appspub.GetInPlaceUpdateState(pod)
+	if !ok {
+		return 0
+	}
+	state := appspub.InPlaceUpdateState{}
+	if err := json.Unmarshal([]byte(stateData), &state); err != nil {
+		return containerStatus.RestartCount
+	}
+	baseline := containerStatus.RestartCount
+	if last, ok := state.LastContainerStatuses[containerStatus.Name]; ok && last.ImageID == containerStatus.ImageID {
+		baseline++
+	}
+	return baseline
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rollback
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	modelsv1alpha1 "github.com/Gentleelephant/EnhancementWorkload/pkg/models/v1alpha1"
+	appspub "github.com/openkruise/kruise-api/apps/pub"
+	"github.com/openkruise/kruise-api/apps/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newCloneSet(paused bool, updatedReady int32) *v1alpha1.CloneSet {
+	replicas := int32(3)
+	return &v1alpha1.CloneSet{
+		ObjectMeta: v1.ObjectMeta{Name: "web", Namespace: "default", Generation: 2},
+		Spec: v1alpha1.CloneSetSpec{
+			Replicas:       &replicas,
+			UpdateStrategy: v1alpha1.CloneSetUpdateStrategy{Paused: paused},
+		},
+		Status: v1alpha1.CloneSetStatus{
+			ObservedGeneration:   2,
+			UpdatedReadyReplicas: updatedReady,
+			CurrentRevision:      "web-v1",
+			UpdateRevision:       "web-v2",
+		},
+	}
+}
+
+func newPod(revision string, restarts int32) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "web-" + revision,
+			Namespace: "default",
+			Labels:    map[string]string{appsv1.ControllerRevisionHashLabelKey: revision},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "main", RestartCount: restarts}},
+		},
+	}
+}
+
+// inPlaceUpdated marks the pod as updated in place from the image, the update restart already happened
+// unless the container still runs that image.
+func inPlaceUpdated(pod *corev1.Pod, lastImageID string) *corev1.Pod {
+	pod.Status.ContainerStatuses[0].ImageID = "image-v2"
+	state := appspub.InPlaceUpdateState{
+		Revision:              pod.Labels[appsv1.ControllerRevisionHashLabelKey],
+		LastContainerStatuses: map[string]appspub.InPlaceUpdateContainerStatus{"main": {ImageID: lastImageID}},
+	}
+	stateData, _ := json.Marshal(state)
+	pod.Annotations = map[string]string{appspub.InPlaceUpdateStateKey: string(stateData)}
+	return pod
+}
+
+func renamed(pod *corev1.Pod, name string) *corev1.Pod {
+	pod.Name = name
+	return pod
+}
+
+func TestEvaluate(t *testing.T) {
+	policy := &modelsv1alpha1.RollbackPolicy{ProgressDeadlineSeconds: 600, MaxRestarts: 3}
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	progressAt := func(d time.Duration) *v1.Time {
+		t := v1.NewTime(now.Add(-d))
+		return &t
+	}
+	watching := func(d time.Duration) modelsv1alpha1.RollbackStatus {
+		return modelsv1alpha1.RollbackStatus{UpdateRevision: "web-v2", TargetReplicas: 3, UpdatedReadyReplicas: 1, LastProgressTime: progressAt(d)}
+	}
+	withBaselines := func(status modelsv1alpha1.RollbackStatus, baselines map[string]int32) modelsv1alpha1.RollbackStatus {
+		status.RestartBaselines = baselines
+		return status
+	}
+	stable := func(cloneSet *v1alpha1.CloneSet) *v1alpha1.CloneSet {
+		cloneSet.Status.CurrentRevision = cloneSet.Status.UpdateRevision
+		return cloneSet
+	}
+	tests := []struct {
+		name        string
+		cloneSet    *v1alpha1.CloneSet
+		pods        []*corev1.Pod
+		status      modelsv1alpha1.RollbackStatus
+		wantStatus  modelsv1alpha1.RollbackStatus
+		wantReason  string
+		wantRequeue time.Duration
+	}{
+		{
+			name:        "starts watching a new rollout",
+			cloneSet:    newCloneSet(false, 1),
+			wantStatus:  watching(0),
+			wantRequeue: 10 * time.Minute,
+		},
+		{
+			name:        "waits for the progress deadline",
+			cloneSet:    newCloneSet(false, 1),
+			pods:        []*corev1.Pod{newPod("web-v2", 1)},
+			status:      watching(4 * time.Minute),
+			wantStatus:  withBaselines(watching(4*time.Minute), map[string]int32{"web-web-v2/main": 0}),
+			wantRequeue: 6 * time.Minute,
+		},
+		{
+			name:       "rolls back when the progress deadline is exceeded",
+			cloneSet:   newCloneSet(false, 1),
+			status:     watching(11 * time.Minute),
+			wantStatus: watching(11 * time.Minute),
+			wantReason: modelsv1alpha1.RollbackReasonProgressDeadline,
+		},
+		{
+			name:       "rolls back when an updated pod crash-loops",
+			cloneSet:   newCloneSet(false, 1),
+			pods:       []*corev1.Pod{newPod("web-v1", 5), newPod("web-v2", 3)},
+			status:     watching(time.Minute),
+			wantStatus: withBaselines(watching(time.Minute), map[string]int32{"web-web-v2/main": 0}),
+			wantReason: modelsv1alpha1.RollbackReasonCrashLoop,
+		},
+		{
+			name:        "ignores restarts from before an in-place update",
+			cloneSet:    newCloneSet(false, 1),
+			pods:        []*corev1.Pod{inPlaceUpdated(newPod("web-v2", 5), "image-v1"), renamed(inPlaceUpdated(newPod("web-v2", 4), "image-v2"), "web-pending")},
+			status:      watching(time.Minute),
+			wantStatus:  withBaselines(watching(time.Minute), map[string]int32{"web-web-v2/main": 5, "web-pending/main": 5}),
+			wantRequeue: 9 * time.Minute,
+		},
+		{
+			name:       "counts restarts since the recorded baseline",
+			cloneSet:   newCloneSet(false, 1),
+			pods:       []*corev1.Pod{newPod("web-v2", 7), renamed(newPod("web-v2", 5), "web-web-v2-b")},
+			status:     withBaselines(watching(time.Minute), map[string]int32{"web-web-v2/main": 4, "web-web-v2-b/main": 2, "web-gone/main": 1}),
+			wantStatus: withBaselines(watching(time.Minute), map[string]int32{"web-web-v2/main": 4, "web-web-v2-b/main": 2}),
+			wantReason: modelsv1alpha1.RollbackReasonCrashLoop,
+		},
+		{
+			name:       "forgets the baselines of a previous rollout",
+			cloneSet:   newCloneSet(false, 1),
+			pods:       []*corev1.Pod{newPod("web-v2", 3)},
+			status:     withBaselines(modelsv1alpha1.RollbackStatus{UpdateRevision: "web-v0", TargetReplicas: 3}, map[string]int32{"web-web-v2/main": 3}),
+			wantStatus: withBaselines(watching(0), map[string]int32{"web-web-v2/main": 0}),
+			wantReason: modelsv1alpha1.RollbackReasonCrashLoop,
+		},
+		{
+			name:       "skips a crash-looping CloneSet without a rollout in progress",
+			cloneSet:   stable(newCloneSet(false, 1)),
+			pods:       []*corev1.Pod{newPod("web-v2", 5)},
+			status:     watching(time.Hour),
+			wantStatus: watching(time.Hour),
+		},
+		{
+			name:       "skips an unready CloneSet without a rollout in progress",
+			cloneSet:   stable(newCloneSet(false, 0)),
+			wantStatus: modelsv1alpha1.RollbackStatus{},
+		},
+		{
+			name:        "ignores restarts of pods at other revisions",
+			cloneSet:    newCloneSet(false, 1),
+			pods:        []*corev1.Pod{newPod("web-v1", 5)},
+			status:      watching(time.Minute),
+			wantStatus:  watching(time.Minute),
+			wantRequeue: 9 * time.Minute,
+		},
+		{
+			name:       "stops watching a finished rollout",
+			cloneSet:   newCloneSet(false, 3),
+			status:     watching(11 * time.Minute),
+			wantStatus: modelsv1alpha1.RollbackStatus{UpdateRevision: "web-v2", TargetReplicas: 3, UpdatedReadyReplicas: 3, LastProgressTime: progressAt(0)},
+		},
+		{
+			name:     "skips a paused rollout",
+			cloneSet: newCloneSet(true, 1),
+			pods:     []*corev1.Pod{newPod("web-v2", 5)},
+			status:   watching(11 * time.Minute),
+			wantStatus: modelsv1alpha1.RollbackStatus{UpdateRevision: "web-v2", TargetReplicas: 3, UpdatedReadyReplicas: 1, Paused: true,
+				LastProgressTime: progressAt(0)},
+		},
+		{
+			name:     "never rolls back the revision restored by the last rollback",
+			cloneSet: newCloneSet(false, 1),
+			pods:     []*corev1.Pod{newPod("web-v2", 5)},
+			status: modelsv1alpha1.RollbackStatus{UpdateRevision: "web-v2", TargetReplicas: 3, UpdatedReadyReplicas: 1, LastProgressTime: progressAt(time.Hour),
+				LastRollback: &modelsv1alpha1.Rollback{FromRevision: "web-v3", ToRevision: "web-v2"}},
+			wantStatus: modelsv1alpha1.RollbackStatus{UpdateRevision: "web-v2", TargetReplicas: 3, UpdatedReadyReplicas: 1, LastProgressTime: progressAt(time.Hour),
+				LastRollback: &modelsv1alpha1.Rollback{FromRevision: "web-v3", ToRevision: "web-v2"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, failure, requeue := evaluate(tt.cloneSet, tt.pods, policy, tt.status, now)
+			assert.Equal(t, tt.wantStatus, status)
+			assert.Equal(t, tt.wantRequeue, requeue)
+			if tt.wantReason == "" {
+				assert.Nil(t, failure)
+				return
+			}
+			if assert.NotNil(t, failure) {
+				assert.Equal(t, tt.wantReason, failure.Reason)
+				assert.Equal(t, "web-v2", failure.FromRevision)
+			}
+		})
+	}
+}
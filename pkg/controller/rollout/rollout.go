@@ -0,0 +1,256 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rollout
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/constants"
+	modelsv1alpha1 "github.com/Gentleelephant/EnhancementWorkload/pkg/models/v1alpha1"
+	"github.com/openkruise/kruise-api/apps/v1alpha1"
+	kruiseclientset "github.com/openkruise/kruise-api/client/clientset/versioned"
+	kruiseinformer "github.com/openkruise/kruise-api/client/informers/externalversions"
+	kruiselisters "github.com/openkruise/kruise-api/client/listers/apps/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+// Controller carries out the rollout plans of CloneSets. It moves the partition of a CloneSet through
+// the steps of its plan once the updated pods of a step are ready and the wait time passed, and pauses
+// the CloneSet while too few of its pods are ready.
+type Controller struct {
+	kruiseclientset kruiseclientset.Interface
+	lister          kruiselisters.CloneSetLister
+	queue           workqueue.RateLimitingInterface
+}
+
+func NewController(clientset kruiseclientset.Interface, informer kruiseinformer.SharedInformerFactory) *Controller {
+	return &Controller{
+		kruiseclientset: clientset,
+		lister:          informer.Apps().V1alpha1().CloneSets().Lister(),
+		queue:           workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "rollout"),
+	}
+}
+
+// Enqueue queues a CloneSet with a rollout plan, it is meant to be called from the CloneSet informer handlers.
+func (c *Controller) Enqueue(obj interface{}) {
+	cloneSet, ok := obj.(*v1alpha1.CloneSet)
+	if !ok {
+		return
+	}
+	if _, ok := cloneSet.Annotations[constants.RolloutPlanAnnotation]; !ok {
+		return
+	}
+	key, err := cache.MetaNamespaceKeyFunc(cloneSet)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.queue.Add(key)
+}
+
+// Run processes queued CloneSets until the context is done.
+func (c *Controller) Run(ctx context.Context, workers int) {
+	defer utilruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	klog.V(0).Info("Starting rollout controller")
+	for i := 0; i < workers; i++ {
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
+	}
+	<-ctx.Done()
+	klog.V(0).Info("Shutting down rollout controller")
+}
+
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextItem(ctx) {
+	}
+}
+
+func (c *Controller) processNextItem(ctx context.Context) bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	requeueAfter, err := c.sync(ctx, key.(string))
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to sync rollout of cloneset %s: %v", key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	if requeueAfter > 0 {
+		c.queue.AddAfter(key, requeueAfter)
+	}
+	return true
+}
+
+func (c *Controller) sync(ctx context.Context, key string) (time.Duration, error) {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return 0, err
+	}
+	cloneSet, err := c.lister.CloneSets(namespace).Get(name)
+	if errors.IsNotFound(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	plan, status, err := modelsv1alpha1.GetRolloutPlan(cloneSet)
+	if err != nil || plan == nil {
+		// an invalid plan is not retried, it is picked up again once the CloneSet changes
+		if err != nil {
+			klog.Error(err)
+		}
+		return 0, nil
+	}
+
+	next, updateStrategy, requeueAfter := reconcile(cloneSet, plan, *status, time.Now())
+	if equality.Semantic.DeepEqual(next, *status) && len(updateStrategy) == 0 {
+		return requeueAfter, nil
+	}
+
+	statusData, err := json.Marshal(next)
+	if err != nil {
+		return 0, err
+	}
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"resourceVersion": cloneSet.ResourceVersion,
+			"annotations": map[string]interface{}{
+				constants.RolloutStatusAnnotation: string(statusData),
+			},
+		},
+	}
+	if len(updateStrategy) > 0 {
+		patch["spec"] = map[string]interface{}{"updateStrategy": updateStrategy}
+	}
+	patchData, err := json.Marshal(patch)
+	if err != nil {
+		return 0, err
+	}
+	if _, err = c.kruiseclientset.AppsV1alpha1().CloneSets(namespace).Patch(ctx, name, types.MergePatchType, patchData, v1.PatchOptions{}); err != nil {
+		return 0, err
+	}
+	if next.Phase != status.Phase {
+		klog.Infof("rollout of cloneset %s is %s at step %d: %s", key, next.Phase, next.CurrentStep, next.Message)
+	}
+	return requeueAfter, nil
+}
+
+// reconcile computes the next status of the rollout plan, the fields of spec.updateStrategy to change and
+// how long to wait before checking the CloneSet again when nothing else changes it.
+func reconcile(cloneSet *v1alpha1.CloneSet, plan *modelsv1alpha1.RolloutPlan, status modelsv1alpha1.RolloutPlanStatus, now time.Time) (modelsv1alpha1.RolloutPlanStatus, map[string]interface{}, time.Duration) {
+	if cloneSet.Status.ObservedGeneration < cloneSet.Generation {
+		// wait for the kruise controller to observe the latest spec
+		return status, nil, 0
+	}
+	if status.UpdateRevision != cloneSet.Status.UpdateRevision {
+		// a new revision is rolled out from the first step again
+		status = modelsv1alpha1.RolloutPlanStatus{
+			Phase:          modelsv1alpha1.RolloutProgressing,
+			UpdateRevision: cloneSet.Status.UpdateRevision,
+		}
+	}
+
+	replicas := int32(1)
+	if cloneSet.Spec.Replicas != nil {
+		replicas = *cloneSet.Spec.Replicas
+	}
+	updateStrategy := make(map[string]interface{})
+	if status.Phase == modelsv1alpha1.RolloutCompleted {
+		// hold every pod so that the next revision waits for the first step instead of rolling out at once
+		holdRevisions(cloneSet, replicas, updateStrategy)
+		return status, updateStrategy, 0
+	}
+
+	partition := modelsv1alpha1.StepPartition(plan.Steps[status.CurrentStep], replicas)
+	if current := cloneSet.Spec.UpdateStrategy.Partition; current == nil || current.String() != fmt.Sprint(partition) {
+		updateStrategy["partition"] = partition
+	}
+
+	readyPercent := int32(100)
+	if replicas > 0 {
+		readyPercent = cloneSet.Status.ReadyReplicas * 100 / replicas
+	}
+	if readyPercent < plan.MinReadyPercent {
+		status.Phase = modelsv1alpha1.RolloutPaused
+		status.Message = fmt.Sprintf("%d%% of the replicas are ready, below the minimum of %d%%", readyPercent, plan.MinReadyPercent)
+		if !cloneSet.Spec.UpdateStrategy.Paused {
+			updateStrategy["paused"] = true
+		}
+		return status, updateStrategy, 0
+	}
+	if status.Phase == modelsv1alpha1.RolloutPaused {
+		// only resume the CloneSet this controller paused
+		status.Phase = modelsv1alpha1.RolloutProgressing
+		status.Message = ""
+		if cloneSet.Spec.UpdateStrategy.Paused {
+			updateStrategy["paused"] = false
+		}
+	}
+	if _, ok := updateStrategy["partition"]; ok {
+		return status, updateStrategy, 0
+	}
+
+	if cloneSet.Status.UpdatedReadyReplicas < replicas-partition {
+		status.Phase = modelsv1alpha1.RolloutProgressing
+		status.StepReadyTime = nil
+		return status, updateStrategy, 0
+	}
+
+	if status.StepReadyTime == nil {
+		readyTime := v1.NewTime(now)
+		status.StepReadyTime = &readyTime
+	}
+	if remaining := status.StepReadyTime.Add(time.Duration(plan.WaitSeconds) * time.Second).Sub(now); remaining > 0 {
+		status.Phase = modelsv1alpha1.RolloutWaiting
+		return status, updateStrategy, remaining
+	}
+
+	if int(status.CurrentStep) == len(plan.Steps)-1 {
+		status.Phase = modelsv1alpha1.RolloutCompleted
+		holdRevisions(cloneSet, replicas, updateStrategy)
+		return status, updateStrategy, 0
+	}
+	status.CurrentStep++
+	status.Phase = modelsv1alpha1.RolloutProgressing
+	status.StepReadyTime = nil
+	updateStrategy["partition"] = modelsv1alpha1.StepPartition(plan.Steps[status.CurrentStep], replicas)
+	return status, updateStrategy, 0
+}
+
+// holdRevisions raises the partition to the replicas, which keeps every pod at the current revision.
+func holdRevisions(cloneSet *v1alpha1.CloneSet, replicas int32, updateStrategy map[string]interface{}) {
+	if current := cloneSet.Spec.UpdateStrategy.Partition; current == nil || current.String() != fmt.Sprint(replicas) {
+		updateStrategy["partition"] = replicas
+	}
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rollout
+
+import (
+	"testing"
+	"time"
+
+	modelsv1alpha1 "github.com/Gentleelephant/EnhancementWorkload/pkg/models/v1alpha1"
+	"github.com/openkruise/kruise-api/apps/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func newCloneSet(partition int, paused bool, ready, updatedReady int32) *v1alpha1.CloneSet {
+	replicas := int32(10)
+	p := intstr.FromInt(partition)
+	return &v1alpha1.CloneSet{
+		ObjectMeta: v1.ObjectMeta{Name: "web", Namespace: "default", Generation: 2},
+		Spec: v1alpha1.CloneSetSpec{
+			Replicas:       &replicas,
+			UpdateStrategy: v1alpha1.CloneSetUpdateStrategy{Partition: &p, Paused: paused},
+		},
+		Status: v1alpha1.CloneSetStatus{
+			ObservedGeneration:   2,
+			ReadyReplicas:        ready,
+			UpdatedReadyReplicas: updatedReady,
+			UpdateRevision:       "web-v2",
+		},
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	plan := &modelsv1alpha1.RolloutPlan{
+		Steps:           []intstr.IntOrString{intstr.FromString("10%"), intstr.FromString("50%"), intstr.FromString("100%")},
+		WaitSeconds:     60,
+		MinReadyPercent: 80,
+	}
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	readyAt := func(d time.Duration) *v1.Time {
+		t := v1.NewTime(now.Add(-d))
+		return &t
+	}
+	tests := []struct {
+		name               string
+		cloneSet           *v1alpha1.CloneSet
+		status             modelsv1alpha1.RolloutPlanStatus
+		wantStatus         modelsv1alpha1.RolloutPlanStatus
+		wantUpdateStrategy map[string]interface{}
+		wantRequeue        time.Duration
+	}{
+		{
+			name:               "sets the partition of the current step",
+			cloneSet:           newCloneSet(10, false, 10, 0),
+			status:             modelsv1alpha1.RolloutPlanStatus{Phase: modelsv1alpha1.RolloutProgressing, UpdateRevision: "web-v2"},
+			wantStatus:         modelsv1alpha1.RolloutPlanStatus{Phase: modelsv1alpha1.RolloutProgressing, UpdateRevision: "web-v2"},
+			wantUpdateStrategy: map[string]interface{}{"partition": int32(9)},
+		},
+		{
+			name:               "waits for the updated pods",
+			cloneSet:           newCloneSet(9, false, 9, 0),
+			status:             modelsv1alpha1.RolloutPlanStatus{Phase: modelsv1alpha1.RolloutProgressing, UpdateRevision: "web-v2"},
+			wantStatus:         modelsv1alpha1.RolloutPlanStatus{Phase: modelsv1alpha1.RolloutProgressing, UpdateRevision: "web-v2"},
+			wantUpdateStrategy: map[string]interface{}{},
+		},
+		{
+			name:               "waits after the step is ready",
+			cloneSet:           newCloneSet(9, false, 10, 1),
+			status:             modelsv1alpha1.RolloutPlanStatus{Phase: modelsv1alpha1.RolloutProgressing, UpdateRevision: "web-v2"},
+			wantStatus:         modelsv1alpha1.RolloutPlanStatus{Phase: modelsv1alpha1.RolloutWaiting, UpdateRevision: "web-v2", StepReadyTime: readyAt(0)},
+			wantUpdateStrategy: map[string]interface{}{},
+			wantRequeue:        time.Minute,
+		},
+		{
+			name:               "advances after the wait time",
+			cloneSet:           newCloneSet(9, false, 10, 1),
+			status:             modelsv1alpha1.RolloutPlanStatus{Phase: modelsv1alpha1.RolloutWaiting, UpdateRevision: "web-v2", StepReadyTime: readyAt(time.Minute)},
+			wantStatus:         modelsv1alpha1.RolloutPlanStatus{Phase: modelsv1alpha1.RolloutProgressing, UpdateRevision: "web-v2", CurrentStep: 1},
+			wantUpdateStrategy: map[string]interface{}{"partition": int32(5)},
+		},
+		{
+			name:               "completes after the last step",
+			cloneSet:           newCloneSet(0, false, 10, 10),
+			status:             modelsv1alpha1.RolloutPlanStatus{Phase: modelsv1alpha1.RolloutWaiting, UpdateRevision: "web-v2", CurrentStep: 2, StepReadyTime: readyAt(2 * time.Minute)},
+			wantStatus:         modelsv1alpha1.RolloutPlanStatus{Phase: modelsv1alpha1.RolloutCompleted, UpdateRevision: "web-v2", CurrentStep: 2, StepReadyTime: readyAt(2 * time.Minute)},
+			wantUpdateStrategy: map[string]interface{}{"partition": int32(10)},
+		},
+		{
+			name:               "holds the next revision after completion",
+			cloneSet:           newCloneSet(10, false, 10, 10),
+			status:             modelsv1alpha1.RolloutPlanStatus{Phase: modelsv1alpha1.RolloutCompleted, UpdateRevision: "web-v2", CurrentStep: 2},
+			wantStatus:         modelsv1alpha1.RolloutPlanStatus{Phase: modelsv1alpha1.RolloutCompleted, UpdateRevision: "web-v2", CurrentStep: 2},
+			wantUpdateStrategy: map[string]interface{}{},
+		},
+		{
+			name:               "holds the scaled replicas after completion",
+			cloneSet:           newCloneSet(8, false, 10, 10),
+			status:             modelsv1alpha1.RolloutPlanStatus{Phase: modelsv1alpha1.RolloutCompleted, UpdateRevision: "web-v2", CurrentStep: 2},
+			wantStatus:         modelsv1alpha1.RolloutPlanStatus{Phase: modelsv1alpha1.RolloutCompleted, UpdateRevision: "web-v2", CurrentStep: 2},
+			wantUpdateStrategy: map[string]interface{}{"partition": int32(10)},
+		},
+		{
+			name:               "pauses when readiness drops",
+			cloneSet:           newCloneSet(5, false, 7, 3),
+			status:             modelsv1alpha1.RolloutPlanStatus{Phase: modelsv1alpha1.RolloutProgressing, UpdateRevision: "web-v2", CurrentStep: 1},
+			wantStatus:         modelsv1alpha1.RolloutPlanStatus{Phase: modelsv1alpha1.RolloutPaused, UpdateRevision: "web-v2", CurrentStep: 1, Message: "70% of the replicas are ready, below the minimum of 80%"},
+			wantUpdateStrategy: map[string]interface{}{"paused": true},
+		},
+		{
+			name:               "resumes when readiness recovers",
+			cloneSet:           newCloneSet(5, true, 9, 4),
+			status:             modelsv1alpha1.RolloutPlanStatus{Phase: modelsv1alpha1.RolloutPaused, UpdateRevision: "web-v2", CurrentStep: 1, Message: "paused"},
+			wantStatus:         modelsv1alpha1.RolloutPlanStatus{Phase: modelsv1alpha1.RolloutProgressing, UpdateRevision: "web-v2", CurrentStep: 1},
+			wantUpdateStrategy: map[string]interface{}{"paused": false},
+		},
+		{
+			name:               "restarts on a new revision",
+			cloneSet:           newCloneSet(10, false, 10, 0),
+			status:             modelsv1alpha1.RolloutPlanStatus{Phase: modelsv1alpha1.RolloutCompleted, UpdateRevision: "web-v1", CurrentStep: 2},
+			wantStatus:         modelsv1alpha1.RolloutPlanStatus{Phase: modelsv1alpha1.RolloutProgressing, UpdateRevision: "web-v2"},
+			wantUpdateStrategy: map[string]interface{}{"partition": int32(9)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, updateStrategy, requeue := reconcile(tt.cloneSet, plan, tt.status, now)
+			assert.Equal(t, tt.wantStatus, status)
+			assert.Equal(t, tt.wantUpdateStrategy, updateStrategy)
+			assert.Equal(t, tt.wantRequeue, requeue)
+		})
+	}
+}
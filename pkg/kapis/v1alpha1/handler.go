@@ -11,9 +11,14 @@ import (
 	"github.com/emicklei/go-restful/v3"
 	v1alpha12 "github.com/openkruise/kruise-api/apps/v1alpha1"
 	kruiseclientset "github.com/openkruise/kruise-api/client/clientset/versioned"
+	"io"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
+	"net/http"
+	"strconv"
 )
 
 type Handler struct {
@@ -32,7 +37,460 @@ func (h *Handler) ListPod(request *restful.Request, response *restful.Response)
 	name := request.QueryParameter("name")
 	resource := request.QueryParameter("resource")
 
-	pods, err := h.operator.ListPods(namespace, resource, name)
+	pods, err := h.operator.ListWorkloadPods(namespace, resource, name)
+	handleResponse(request, response, pods, err)
+}
+
+func (h *Handler) ListWorkloadPods(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	resources := request.PathParameter("resources")
+	name := request.PathParameter("name")
+
+	pods, err := h.operator.ListWorkloadPods(namespace, resources, name)
+	handleResponse(request, response, pods, err)
+}
+
+func (h *Handler) CompletePodLifecycleHook(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	resources := request.PathParameter("resources")
+	name := request.PathParameter("name")
+	podName := request.PathParameter("pod")
+
+	pod, err := h.operator.CompletePodLifecycleHook(namespace, resources, name, podName)
+	handleResponse(request, response, pod, err)
+}
+
+func (h *Handler) ListAdvancedCronJobRuns(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	runs, err := h.operator.ListAdvancedCronJobRuns(namespace, name)
+	handleResponse(request, response, runs, err)
+}
+
+func (h *Handler) ListUnitedDeploymentSubsets(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	subsets, err := h.operator.ListUnitedDeploymentSubsets(namespace, name)
+	handleResponse(request, response, subsets, err)
+}
+
+func (h *Handler) GetCloneSetSpread(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	spread, err := h.operator.GetCloneSetSpread(namespace, name)
+	handleResponse(request, response, spread, err)
+}
+
+func (h *Handler) GetCloneSetPodUnavailableBudget(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	budget, err := h.operator.GetCloneSetPodUnavailableBudget(namespace, name)
+	handleResponse(request, response, budget, err)
+}
+
+func (h *Handler) RestartCloneSetPod(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+	pod := request.PathParameter("pod")
+
+	restart := &v1alpha1.RestartContainers{}
+	if err := request.ReadEntity(restart); err != nil {
+		api.HandleBadRequest(response, request, err)
+		return
+	}
+
+	created, err := h.operator.RestartCloneSetPod(namespace, name, pod, restart)
+	handleResponse(request, response, created, err)
+}
+
+func (h *Handler) ListCloneSetPodRestarts(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+	pod := request.PathParameter("pod")
+
+	restarts, err := h.operator.ListCloneSetPodRestarts(namespace, name, pod)
+	handleResponse(request, response, restarts, err)
+}
+
+func (h *Handler) PullCloneSetImages(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	pull := &v1alpha1.PullImages{}
+	if err := request.ReadEntity(pull); err != nil {
+		api.HandleBadRequest(response, request, err)
+		return
+	}
+
+	created, err := h.operator.PullCloneSetImages(namespace, name, pull)
+	handleResponse(request, response, created, err)
+}
+
+func (h *Handler) GetCloneSetScale(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	scale, err := h.operator.GetCloneSetScale(namespace, name)
+	handleResponse(request, response, scale, err)
+}
+
+func (h *Handler) ScaleCloneSet(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	scale := &v1alpha1.CloneSetScale{}
+	if err := request.ReadEntity(scale); err != nil {
+		api.HandleBadRequest(response, request, err)
+		return
+	}
+
+	updated, err := h.operator.ScaleCloneSet(namespace, name, scale)
+	handleResponse(request, response, updated, err)
+}
+
+func (h *Handler) DeleteCloneSetPods(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	pods := &v1alpha1.DeleteCloneSetPods{}
+	if err := request.ReadEntity(pods); err != nil {
+		api.HandleBadRequest(response, request, err)
+		return
+	}
+
+	cloneSet, err := h.operator.DeleteCloneSetPods(namespace, name, pods)
+	handleResponse(request, response, cloneSet, err)
+}
+
+func (h *Handler) PauseCloneSetRollout(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	rollout, err := h.operator.PauseCloneSetRollout(namespace, name)
+	handleResponse(request, response, rollout, err)
+}
+
+func (h *Handler) ResumeCloneSetRollout(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	rollout, err := h.operator.ResumeCloneSetRollout(namespace, name)
+	handleResponse(request, response, rollout, err)
+}
+
+func (h *Handler) StepCloneSetRollout(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	step := &v1alpha1.RolloutStep{}
+	if err := request.ReadEntity(step); err != nil {
+		api.HandleBadRequest(response, request, err)
+		return
+	}
+
+	rollout, err := h.operator.StepCloneSetRollout(namespace, name, step)
+	handleResponse(request, response, rollout, err)
+}
+
+func (h *Handler) SetCloneSetRolloutPlan(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	plan := &v1alpha1.RolloutPlan{}
+	if err := request.ReadEntity(plan); err != nil {
+		api.HandleBadRequest(response, request, err)
+		return
+	}
+
+	rolloutPlan, err := h.operator.SetCloneSetRolloutPlan(namespace, name, plan)
+	handleResponse(request, response, rolloutPlan, err)
+}
+
+func (h *Handler) GetCloneSetRolloutPlan(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	rolloutPlan, err := h.operator.GetCloneSetRolloutPlan(namespace, name)
+	handleResponse(request, response, rolloutPlan, err)
+}
+
+func (h *Handler) DeleteCloneSetRolloutPlan(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	handleResponse(request, response, serrors.None, h.operator.DeleteCloneSetRolloutPlan(namespace, name))
+}
+
+func (h *Handler) SetCloneSetRollbackPolicy(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	policy := &v1alpha1.RollbackPolicy{}
+	if err := request.ReadEntity(policy); err != nil {
+		api.HandleBadRequest(response, request, err)
+		return
+	}
+
+	autoRollback, err := h.operator.SetCloneSetRollbackPolicy(namespace, name, policy)
+	handleResponse(request, response, autoRollback, err)
+}
+
+func (h *Handler) GetCloneSetRollbackPolicy(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	autoRollback, err := h.operator.GetCloneSetRollbackPolicy(namespace, name)
+	handleResponse(request, response, autoRollback, err)
+}
+
+func (h *Handler) DeleteCloneSetRollbackPolicy(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	handleResponse(request, response, serrors.None, h.operator.DeleteCloneSetRollbackPolicy(namespace, name))
+}
+
+func (h *Handler) ListCloneSetRevisions(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	revisions, err := h.operator.ListCloneSetRevisionHistory(namespace, name)
+	handleResponse(request, response, revisions, err)
+}
+
+func (h *Handler) RollbackCloneSet(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	rollback := &v1alpha1.CloneSetRollback{}
+	if err := request.ReadEntity(rollback); err != nil {
+		api.HandleBadRequest(response, request, err)
+		return
+	}
+
+	cloneSet, err := h.operator.RollbackCloneSetToRevision(namespace, name, rollback)
+	handleResponse(request, response, cloneSet, err)
+}
+
+func (h *Handler) DiffCloneSetRevisions(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	from, to, err := revisionRange(request)
+	if err != nil {
+		api.HandleBadRequest(response, request, err)
+		return
+	}
+
+	diff, err := h.operator.DiffCloneSetRevisions(namespace, name, from, to)
+	handleResponse(request, response, diff, err)
+}
+
+func (h *Handler) DiffSidecarSetRevisions(request *restful.Request, response *restful.Response) {
+	name := request.PathParameter("name")
+
+	from, to, err := revisionRange(request)
+	if err != nil {
+		api.HandleBadRequest(response, request, err)
+		return
+	}
+
+	diff, err := h.operator.DiffSidecarSetRevisions(name, from, to)
+	handleResponse(request, response, diff, err)
+}
+
+func (h *Handler) DiffResource(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	resources := request.PathParameter("resources")
+	name := request.PathParameter("name")
+
+	if !h.operator.IsKnownResource(resources) {
+		api.HandleBadRequest(response, request, serrors.New("unknown resource type %s", resources))
+		return
+	}
+
+	obj := h.operator.GetObject(resources)
+	if err := request.ReadEntity(obj); err != nil {
+		api.HandleBadRequest(response, request, err)
+		return
+	}
+
+	diff, err := h.operator.DiffResource(namespace, resources, name, obj)
+	handleResponse(request, response, diff, err)
+}
+
+// revisionRange reads the from and to revision numbers of a diff, 0 if not given.
+func revisionRange(request *restful.Request) (int64, int64, error) {
+	var revisions [2]int64
+	for i, param := range []string{"from", "to"} {
+		value := request.QueryParameter(param)
+		if value == "" {
+			continue
+		}
+		revision, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid %s revision %q", param, value)
+		}
+		revisions[i] = revision
+	}
+	return revisions[0], revisions[1], nil
+}
+
+func (h *Handler) SetCloneSetImages(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	images := &v1alpha1.SetCloneSetImages{}
+	if err := request.ReadEntity(images); err != nil {
+		api.HandleBadRequest(response, request, err)
+		return
+	}
+
+	update, err := h.operator.SetCloneSetImages(namespace, name, images)
+	handleResponse(request, response, update, err)
+}
+
+func (h *Handler) GetCloneSetStatus(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	status, err := h.operator.GetCloneSetStatus(namespace, name)
+	handleResponse(request, response, status, err)
+}
+
+func (h *Handler) ListWorkloadEvents(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	resources := request.PathParameter("resources")
+	name := request.PathParameter("name")
+
+	if !h.operator.IsKnownResource(resources) {
+		api.HandleBadRequest(response, request, serrors.New("unknown resource type %s", resources))
+		return
+	}
+
+	events, err := h.operator.ListWorkloadEvents(namespace, resources, name)
+	handleResponse(request, response, events, err)
+}
+
+func (h *Handler) GetPodLogs(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	podName := request.PathParameter("pod")
+
+	options, err := podLogOptions(request)
+	if err != nil {
+		api.HandleBadRequest(response, request, err)
+		return
+	}
+
+	logs, err := h.operator.StreamPodLogs(request.Request.Context(), namespace, podName, options)
+	streamLogs(request, response, logs, err)
+}
+
+func (h *Handler) GetCloneSetLogs(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	options, err := podLogOptions(request)
+	if err != nil {
+		api.HandleBadRequest(response, request, err)
+		return
+	}
+
+	logs, err := h.operator.StreamCloneSetLogs(request.Request.Context(), namespace, name, options)
+	streamLogs(request, response, logs, err)
+}
+
+// podLogOptions reads the log options from the query parameters.
+func podLogOptions(request *restful.Request) (*corev1.PodLogOptions, error) {
+	options := &corev1.PodLogOptions{Container: request.QueryParameter("container")}
+	for _, param := range []struct {
+		name  string
+		value **int64
+	}{{"tailLines", &options.TailLines}, {"sinceSeconds", &options.SinceSeconds}} {
+		value := request.QueryParameter(param.name)
+		if value == "" {
+			continue
+		}
+		number, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || number < 0 {
+			return nil, fmt.Errorf("invalid %s %q", param.name, value)
+		}
+		*param.value = &number
+	}
+	for _, param := range []struct {
+		name  string
+		value *bool
+	}{{"previous", &options.Previous}, {"follow", &options.Follow}} {
+		value := request.QueryParameter(param.name)
+		if value == "" {
+			continue
+		}
+		flag, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q", param.name, value)
+		}
+		*param.value = flag
+	}
+	if options.SinceSeconds != nil && *options.SinceSeconds == 0 {
+		return nil, fmt.Errorf("sinceSeconds must be positive")
+	}
+	return options, nil
+}
+
+// streamLogs copies the logs to the response in chunks, flushing each one so followed logs show up
+// as they are written.
+func streamLogs(request *restful.Request, response *restful.Response, logs io.ReadCloser, err error) {
+	if err != nil {
+		handleResponse(request, response, nil, err)
+		return
+	}
+	defer logs.Close()
+
+	response.AddHeader("Content-Type", "text/plain; charset=utf-8")
+	response.WriteHeader(http.StatusOK)
+	buffer := make([]byte, 32*1024)
+	for {
+		n, err := logs.Read(buffer)
+		if n > 0 {
+			if _, writeErr := response.Write(buffer[:n]); writeErr != nil {
+				klog.V(4).Info(writeErr)
+				return
+			}
+			response.Flush()
+		}
+		if err != nil {
+			if err != io.EOF && request.Request.Context().Err() == nil {
+				klog.Error(err)
+			}
+			return
+		}
+	}
+}
+
+func (h *Handler) ListResourceDistributionTargets(request *restful.Request, response *restful.Response) {
+	name := request.PathParameter("name")
+
+	targets, err := h.operator.ListResourceDistributionTargets(name)
+	handleResponse(request, response, targets, err)
+}
+
+func (h *Handler) ListPodProbeResults(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	results, err := h.operator.ListPodProbeResults(namespace, name)
+	handleResponse(request, response, results, err)
+}
+
+func (h *Handler) ListEphemeralJobPods(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	pods, err := h.operator.ListEphemeralJobPods(namespace, name)
 	handleResponse(request, response, pods, err)
 }
 
@@ -92,6 +550,46 @@ func (h *Handler) GetResource(request *restful.Request, response *restful.Respon
 	handleResponse(request, response, obj, err)
 }
 
+func (h *Handler) ListNativeResource(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	resources := request.PathParameter("resources")
+	q := query.ParseQueryParameter(request)
+
+	objs, err := h.operator.ListNative(namespace, resources, q)
+	handleResponse(request, response, objs, err)
+}
+
+func (h *Handler) GetNativeResource(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	resources := request.PathParameter("resources")
+	name := request.PathParameter("name")
+
+	obj, err := h.operator.GetNative(namespace, resources, name)
+	handleResponse(request, response, obj, err)
+}
+
+func (h *Handler) MigrateDeployment(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	migrate := &v1alpha1.MigrateDeployment{}
+	if err := request.ReadEntity(migrate); err != nil {
+		api.HandleBadRequest(response, request, err)
+		return
+	}
+
+	migration, err := h.operator.MigrateDeployment(namespace, name, migrate)
+	handleResponse(request, response, migration, err)
+}
+
+func (h *Handler) GetDeploymentMigration(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	status, err := h.operator.GetDeploymentMigration(namespace, name)
+	handleResponse(request, response, status, err)
+}
+
 func (h *Handler) CreateResource(request *restful.Request, response *restful.Response) {
 	namespace := request.PathParameter("namespace")
 	resources := request.PathParameter("resources")
@@ -168,6 +666,19 @@ func (h *Handler) DeleteResource(request *restful.Request, response *restful.Res
 	handleResponse(request, response, serrors.None, h.operator.Delete(namespace, resources, name))
 }
 
+// groupVersionFilter rejects requests whose resources path parameter is not a resource of the group version,
+// so the generic routes of a web service only serve the resources of its own group version.
+func (h *Handler) groupVersionFilter(groupVersion schema.GroupVersion) restful.FilterFunction {
+	return func(request *restful.Request, response *restful.Response, chain *restful.FilterChain) {
+		resources := request.PathParameter("resources")
+		if resources != "" && !h.operator.IsKnownGroupVersionResource(groupVersion.WithResource(resources)) {
+			api.HandleBadRequest(response, request, serrors.New("unknown resource type %s in %s", resources, groupVersion))
+			return
+		}
+		chain.ProcessFilter(request, response)
+	}
+}
+
 func handleResponse(req *restful.Request, resp *restful.Response, obj interface{}, err error) {
 	if err != nil {
 		klog.Error(err)
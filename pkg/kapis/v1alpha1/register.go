@@ -6,12 +6,16 @@ import (
 	"github.com/Gentleelephant/EnhancementWorkload/pkg/apiserver/runtime"
 	"github.com/Gentleelephant/EnhancementWorkload/pkg/constants"
 	"github.com/Gentleelephant/EnhancementWorkload/pkg/informers"
+	modelsv1alpha1 "github.com/Gentleelephant/EnhancementWorkload/pkg/models/v1alpha1"
 	serrors "github.com/Gentleelephant/EnhancementWorkload/pkg/server/errors"
 	openapi "github.com/emicklei/go-restful-openapi"
 	"github.com/emicklei/go-restful/v3"
 	"github.com/go-openapi/spec"
 	"github.com/openkruise/kruise-api/apps/v1alpha1"
+	"github.com/openkruise/kruise-api/apps/v1beta1"
 	kruiseclientset "github.com/openkruise/kruise-api/client/clientset/versioned"
+	policyv1alpha1 "github.com/openkruise/kruise-api/policy/v1alpha1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes"
 	"net/http"
@@ -19,6 +23,12 @@ import (
 
 var GroupVersion = schema.GroupVersion{Group: "apps.kruise.io", Version: "v1alpha1"}
 
+var BetaGroupVersion = schema.GroupVersion{Group: "apps.kruise.io", Version: "v1beta1"}
+
+var PolicyGroupVersion = schema.GroupVersion{Group: "policy.kruise.io", Version: "v1alpha1"}
+
+var NativeGroupVersion = schema.GroupVersion{Group: "apps", Version: "v1"}
+
 func SwaggerObject(swo *spec.Swagger) {
 	swo.Info = &spec.Info{
 		InfoProps: spec.InfoProps{
@@ -55,13 +65,14 @@ func AddToContainer(container *restful.Container, informers informers.InformerFa
 
 	ws := runtime.NewWebService(GroupVersion)
 	h := NewKruiseHandler(informers, clientset, k8sclient)
+	ws.Filter(h.groupVersionFilter(GroupVersion))
 
 	// list all cloneset/sidecarset in all namespaces
 	ws.Route(ws.GET("/{resources}").
 		To(h.ListResource).
 		Doc("List the cloneset object or sidecarset object in all namespace").
 		Metadata(openapi.KeyOpenAPITags, []string{constants.Common}).
-		Param(ws.PathParameter("resources", "known values include cloneset, sidecarset").Required(true)).
+		Param(ws.PathParameter("resources", "known values include clonesets, sidecarsets, nodeimages, resourcedistributions, nodepodprobes, daemonsets, broadcastjobs, advancedcronjobs, uniteddeployments, workloadspreads, containerrecreaterequests, imagepulljobs, podprobemarkers, persistentpodstates, ephemeraljobs").Required(true)).
 		Param(ws.QueryParameter(query.ParameterPage, "page").Required(false).DataFormat("page=%d").DefaultValue("page=1")).
 		Param(ws.QueryParameter(query.ParameterLimit, "limit").Required(false)).
 		Param(ws.QueryParameter(query.ParameterAscending, "sort parameters, e.g. ascending=false").Required(false).DefaultValue("ascending=false")).
@@ -83,10 +94,49 @@ func AddToContainer(container *restful.Container, informers informers.InformerFa
 		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
 		Returns(http.StatusOK, api.StatusOK, api.ListResult{Items: []interface{}{}}))
 
+	// get logs of pods
+	ws.Route(ws.GET("/namespaces/{namespace}/pods/{pod}/log").
+		To(h.GetPodLogs).
+		Doc("Get the logs of a container of the pod, streamed in chunks").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.PodType}).
+		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
+		Param(ws.PathParameter("pod", "name of the pod").Required(true)).
+		Param(ws.QueryParameter("container", "container to show the logs of, defaults to the default container of the pod").Required(false)).
+		Param(ws.QueryParameter("tailLines", "number of lines from the end of the logs to show").Required(false).DataType("integer")).
+		Param(ws.QueryParameter("sinceSeconds", "show only logs newer than this many seconds").Required(false).DataType("integer")).
+		Param(ws.QueryParameter("previous", "show the logs of the previous terminated container").Required(false).DataType("boolean").DefaultValue("false")).
+		Param(ws.QueryParameter("follow", "stream the logs as they are written").Required(false).DataType("boolean").DefaultValue("false")).
+		Produces(restful.MIME_JSON, "text/plain").
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, ""))
+
 	registerCloneSetApi(ws, h)
 	registerSidecarSetApi(ws, h)
+	registerAdvancedCronJobApi(ws, h)
+	registerUnitedDeploymentApi(ws, h)
+	registerResourceDistributionApi(ws, h)
+	registerPodProbeMarkerApi(ws, h)
+	registerEphemeralJobApi(ws, h)
 
 	container.Add(ws)
+
+	betaws := runtime.NewWebService(BetaGroupVersion)
+	betaws.Filter(h.groupVersionFilter(BetaGroupVersion))
+	registerStatefulSetApi(betaws, h)
+
+	container.Add(betaws)
+
+	policyws := runtime.NewWebService(PolicyGroupVersion)
+	policyws.Filter(h.groupVersionFilter(PolicyGroupVersion))
+	registerPodUnavailableBudgetApi(policyws, h)
+
+	container.Add(policyws)
+
+	nativews := runtime.NewWebService(NativeGroupVersion)
+	nativews.Filter(h.groupVersionFilter(NativeGroupVersion))
+	registerNativeApi(nativews, h)
+
+	container.Add(nativews)
 	return nil
 }
 
@@ -97,7 +147,7 @@ func registerCloneSetApi(ws *restful.WebService, h *Handler) {
 		To(h.ListResource).
 		Doc("List the cloneset object in all namespace").
 		Metadata(openapi.KeyOpenAPITags, []string{constants.CloneSetType}).
-		Param(ws.PathParameter("resources", "known values include sidecarset").Required(true)).
+		Param(ws.PathParameter("resources", "known values include clonesets, daemonsets, broadcastjobs, advancedcronjobs, uniteddeployments, workloadspreads, containerrecreaterequests, imagepulljobs, podprobemarkers, persistentpodstates, ephemeraljobs").Required(true)).
 		Param(ws.QueryParameter(query.ParameterPage, "page").Required(false).DataFormat("page=%d").DefaultValue("page=1")).
 		Param(ws.QueryParameter(query.ParameterLimit, "limit").Required(false)).
 		Param(ws.QueryParameter(query.ParameterAscending, "sort parameters, e.g. ascending=false").Required(false).DefaultValue("ascending=false")).
@@ -113,20 +163,364 @@ func registerCloneSetApi(ws *restful.WebService, h *Handler) {
 		Doc("Get the cloneset object in the specified namespace").
 		Metadata(openapi.KeyOpenAPITags, []string{constants.CloneSetType}).
 		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
-		Param(ws.PathParameter("resources", "known values include cloneset, sidecarset").Required(true)).
+		Param(ws.PathParameter("resources", "known values include clonesets, daemonsets, broadcastjobs, advancedcronjobs, uniteddeployments, workloadspreads, containerrecreaterequests, imagepulljobs, podprobemarkers, persistentpodstates, ephemeraljobs").Required(true)).
 		Param(ws.PathParameter("name", "name of the cloneset").Required(true)).
 		Writes(v1alpha1.CloneSet{}).
 		Produces(restful.MIME_JSON).
 		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
 		Returns(http.StatusOK, api.StatusOK, v1alpha1.CloneSet{}))
 
+	// list pods of clonesets
+	ws.Route(ws.GET("/namespaces/{namespace}/{resources}/{name}/pods").
+		To(h.ListWorkloadPods).
+		Doc("List the pods of the cloneset or daemonset in the specified namespace").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.CloneSetType}).
+		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
+		Param(ws.PathParameter("resources", "known values include clonesets, daemonsets, broadcastjobs, advancedcronjobs, uniteddeployments, workloadspreads, containerrecreaterequests, imagepulljobs, podprobemarkers, persistentpodstates, ephemeraljobs").Required(true)).
+		Param(ws.PathParameter("name", "name of the workload").Required(true)).
+		Writes(api.ListResult{Items: []interface{}{}}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, api.ListResult{Items: []interface{}{}}))
+
+	// complete the lifecycle hook of a pod of clonesets
+	ws.Route(ws.POST("/namespaces/{namespace}/{resources}/{name}/pods/{pod}/lifecycle/complete").
+		To(h.CompletePodLifecycleHook).
+		Doc("Remove the finalizers and labels of the preDelete or inPlaceUpdate hook the pod waits for, so it goes on being deleted or updated").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.CloneSetType}).
+		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
+		Param(ws.PathParameter("resources", "known values include clonesets").Required(true)).
+		Param(ws.PathParameter("name", "name of the cloneset").Required(true)).
+		Param(ws.PathParameter("pod", "name of the pod").Required(true)).
+		Writes(modelsv1alpha1.LifecyclePod{}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, modelsv1alpha1.LifecyclePod{}))
+
+	// list events of clonesets
+	ws.Route(ws.GET("/namespaces/{namespace}/{resources}/{name}/events").
+		To(h.ListWorkloadEvents).
+		Doc("List the events of the cloneset and its pods, merged and sorted by the time they last occurred").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.CloneSetType}).
+		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
+		Param(ws.PathParameter("resources", "known values include clonesets, daemonsets, broadcastjobs, advancedcronjobs, uniteddeployments, workloadspreads, containerrecreaterequests, imagepulljobs, podprobemarkers, persistentpodstates, ephemeraljobs").Required(true)).
+		Param(ws.PathParameter("name", "name of the cloneset").Required(true)).
+		Writes(api.ListResult{Items: []interface{}{}}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, api.ListResult{Items: []interface{}{}}))
+
+	// get the workloadspread distribution of clonesets
+	ws.Route(ws.GET("/namespaces/{namespace}/clonesets/{name}/spread").
+		To(h.GetCloneSetSpread).
+		Doc("Get the workloadspread applied to the cloneset and how its pods are distributed over the subsets").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.CloneSetType}).
+		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
+		Param(ws.PathParameter("name", "name of the cloneset").Required(true)).
+		Writes(modelsv1alpha1.CloneSetSpread{}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, modelsv1alpha1.CloneSetSpread{}))
+
+	// get the podunavailablebudget covering clonesets
+	ws.Route(ws.GET("/namespaces/{namespace}/clonesets/{name}/podunavailablebudget").
+		To(h.GetCloneSetPodUnavailableBudget).
+		Doc("Get the podunavailablebudget covering the cloneset and its current unavailableAllowed").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.CloneSetType}).
+		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
+		Param(ws.PathParameter("name", "name of the cloneset").Required(true)).
+		Writes(modelsv1alpha1.WorkloadPodUnavailableBudget{}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, modelsv1alpha1.WorkloadPodUnavailableBudget{}))
+
+	// restart containers of a cloneset pod in place
+	ws.Route(ws.POST("/namespaces/{namespace}/clonesets/{name}/pods/{pod}/restart").
+		To(h.RestartCloneSetPod).
+		Doc("Create a containerrecreaterequest restarting the selected containers of a cloneset pod in place").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.CloneSetType}).
+		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
+		Param(ws.PathParameter("name", "name of the cloneset").Required(true)).
+		Param(ws.PathParameter("pod", "name of the pod").Required(true)).
+		Reads(modelsv1alpha1.RestartContainers{}).
+		Writes(v1alpha1.ContainerRecreateRequest{}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, v1alpha1.ContainerRecreateRequest{}))
+
+	// list the in-place restarts of a cloneset pod
+	ws.Route(ws.GET("/namespaces/{namespace}/clonesets/{name}/pods/{pod}/restarts").
+		To(h.ListCloneSetPodRestarts).
+		Doc("List the containerrecreaterequests of a cloneset pod, newest first, with the recreation phase of each container").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.CloneSetType}).
+		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
+		Param(ws.PathParameter("name", "name of the cloneset").Required(true)).
+		Param(ws.PathParameter("pod", "name of the pod").Required(true)).
+		Writes(api.ListResult{Items: []interface{}{}}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, api.ListResult{Items: []interface{}{}}))
+
+	// pre-pull the images of clonesets
+	ws.Route(ws.POST("/namespaces/{namespace}/clonesets/{name}/imagepulljobs").
+		To(h.PullCloneSetImages).
+		Doc("Create imagepulljobs for the images in the cloneset pod template").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.CloneSetType}).
+		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
+		Param(ws.PathParameter("name", "name of the cloneset").Required(true)).
+		Reads(modelsv1alpha1.PullImages{}).
+		Writes(api.ListResult{Items: []interface{}{}}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, api.ListResult{Items: []interface{}{}}))
+
+	// get the scale of clonesets
+	ws.Route(ws.GET("/namespaces/{namespace}/clonesets/{name}/scale").
+		To(h.GetCloneSetScale).
+		Doc("Get the scale subresource of the cloneset").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.CloneSetType}).
+		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
+		Param(ws.PathParameter("name", "name of the cloneset").Required(true)).
+		Writes(autoscalingv1.Scale{}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, autoscalingv1.Scale{}))
+
+	// scale clonesets
+	ws.Route(ws.PUT("/namespaces/{namespace}/clonesets/{name}/scale").
+		To(h.ScaleCloneSet).
+		Doc("Set the replicas of the cloneset, optionally choosing the pods removed on scale-in").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.CloneSetType}).
+		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
+		Param(ws.PathParameter("name", "name of the cloneset").Required(true)).
+		Reads(modelsv1alpha1.CloneSetScale{}).
+		Writes(autoscalingv1.Scale{}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, autoscalingv1.Scale{}))
+
+	// delete specific pods of clonesets
+	ws.Route(ws.POST("/namespaces/{namespace}/clonesets/{name}/podstodelete").
+		To(h.DeleteCloneSetPods).
+		Doc("Add pods of the cloneset to scaleStrategy.podsToDelete, optionally decrementing the replicas").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.CloneSetType}).
+		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
+		Param(ws.PathParameter("name", "name of the cloneset").Required(true)).
+		Reads(modelsv1alpha1.DeleteCloneSetPods{}).
+		Writes(v1alpha1.CloneSet{}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, v1alpha1.CloneSet{}))
+
+	// pause the rollout of clonesets
+	ws.Route(ws.POST("/namespaces/{namespace}/clonesets/{name}/rollout/pause").
+		To(h.PauseCloneSetRollout).
+		Doc("Pause the rollout of the cloneset, refused with a conflict while a rollout plan drives it").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.CloneSetType}).
+		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
+		Param(ws.PathParameter("name", "name of the cloneset").Required(true)).
+		Writes(modelsv1alpha1.CloneSetRollout{}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, modelsv1alpha1.CloneSetRollout{}))
+
+	// resume the rollout of clonesets
+	ws.Route(ws.POST("/namespaces/{namespace}/clonesets/{name}/rollout/resume").
+		To(h.ResumeCloneSetRollout).
+		Doc("Resume the rollout of the cloneset, refused with a conflict while a rollout plan drives it").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.CloneSetType}).
+		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
+		Param(ws.PathParameter("name", "name of the cloneset").Required(true)).
+		Writes(modelsv1alpha1.CloneSetRollout{}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, modelsv1alpha1.CloneSetRollout{}))
+
+	// step the rollout of clonesets
+	ws.Route(ws.POST("/namespaces/{namespace}/clonesets/{name}/rollout/step").
+		To(h.StepCloneSetRollout).
+		Doc("Set the partition of the cloneset to a number or percentage, or advance the rollout by a number of pods").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.CloneSetType}).
+		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
+		Param(ws.PathParameter("name", "name of the cloneset").Required(true)).
+		Reads(modelsv1alpha1.RolloutStep{}).
+		Writes(modelsv1alpha1.CloneSetRollout{}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, modelsv1alpha1.CloneSetRollout{}))
+
+	// set the rollout plan of clonesets
+	ws.Route(ws.PUT("/namespaces/{namespace}/clonesets/{name}/rollout/plan").
+		To(h.SetCloneSetRolloutPlan).
+		Doc("Drive the rollout of the cloneset through the steps of the plan, pausing while too few pods are ready").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.CloneSetType}).
+		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
+		Param(ws.PathParameter("name", "name of the cloneset").Required(true)).
+		Reads(modelsv1alpha1.RolloutPlan{}).
+		Writes(modelsv1alpha1.CloneSetRolloutPlan{}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, modelsv1alpha1.CloneSetRolloutPlan{}))
+
+	// get the rollout plan of clonesets
+	ws.Route(ws.GET("/namespaces/{namespace}/clonesets/{name}/rollout/plan").
+		To(h.GetCloneSetRolloutPlan).
+		Doc("Get the rollout plan of the cloneset and its progress").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.CloneSetType}).
+		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
+		Param(ws.PathParameter("name", "name of the cloneset").Required(true)).
+		Writes(modelsv1alpha1.CloneSetRolloutPlan{}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, modelsv1alpha1.CloneSetRolloutPlan{}))
+
+	// delete the rollout plan of clonesets
+	ws.Route(ws.DELETE("/namespaces/{namespace}/clonesets/{name}/rollout/plan").
+		To(h.DeleteCloneSetRolloutPlan).
+		Doc("Stop driving the rollout of the cloneset, leaving its partition as it is").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.CloneSetType}).
+		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
+		Param(ws.PathParameter("name", "name of the cloneset").Required(true)).
+		Writes(serrors.None).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, serrors.None))
+
+	// set the rollback policy of clonesets
+	ws.Route(ws.PUT("/namespaces/{namespace}/clonesets/{name}/autorollback").
+		To(h.SetCloneSetRollbackPolicy).
+		Doc("Roll the cloneset back to its previous revision when updated pods crash-loop or the rollout makes no progress within the deadline").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.CloneSetType}).
+		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
+		Param(ws.PathParameter("name", "name of the cloneset").Required(true)).
+		Reads(modelsv1alpha1.RollbackPolicy{}).
+		Writes(modelsv1alpha1.CloneSetAutoRollback{}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, modelsv1alpha1.CloneSetAutoRollback{}))
+
+	// get the rollback policy of clonesets
+	ws.Route(ws.GET("/namespaces/{namespace}/clonesets/{name}/autorollback").
+		To(h.GetCloneSetRollbackPolicy).
+		Doc("Get the rollback policy of the cloneset and the last automatic rollback").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.CloneSetType}).
+		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
+		Param(ws.PathParameter("name", "name of the cloneset").Required(true)).
+		Writes(modelsv1alpha1.CloneSetAutoRollback{}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, modelsv1alpha1.CloneSetAutoRollback{}))
+
+	// delete the rollback policy of clonesets
+	ws.Route(ws.DELETE("/namespaces/{namespace}/clonesets/{name}/autorollback").
+		To(h.DeleteCloneSetRollbackPolicy).
+		Doc("Disable the automatic rollback of the cloneset").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.CloneSetType}).
+		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
+		Param(ws.PathParameter("name", "name of the cloneset").Required(true)).
+		Writes(serrors.None).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, serrors.None))
+
+	// list revisions of clonesets
+	ws.Route(ws.GET("/namespaces/{namespace}/clonesets/{name}/revisions").
+		To(h.ListCloneSetRevisions).
+		Doc("List the controller revisions of the cloneset, newest first").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.CloneSetType}).
+		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
+		Param(ws.PathParameter("name", "name of the cloneset").Required(true)).
+		Writes([]modelsv1alpha1.CloneSetRevision{}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, []modelsv1alpha1.CloneSetRevision{}))
+
+	// rollback clonesets
+	ws.Route(ws.POST("/namespaces/{namespace}/clonesets/{name}/rollback").
+		To(h.RollbackCloneSet).
+		Doc("Restore the pod template of the cloneset from one of its revisions").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.CloneSetType}).
+		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
+		Param(ws.PathParameter("name", "name of the cloneset").Required(true)).
+		Reads(modelsv1alpha1.CloneSetRollback{}).
+		Writes(v1alpha1.CloneSet{}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, v1alpha1.CloneSet{}))
+
+	// diff revisions of clonesets
+	ws.Route(ws.GET("/namespaces/{namespace}/clonesets/{name}/revisions/diff").
+		To(h.DiffCloneSetRevisions).
+		Doc("Diff two revisions of the cloneset, by default the update revision with the one before it").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.CloneSetType}).
+		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
+		Param(ws.PathParameter("name", "name of the cloneset").Required(true)).
+		Param(ws.QueryParameter("from", "revision number to diff from, defaults to the revision before to").Required(false)).
+		Param(ws.QueryParameter("to", "revision number to diff to, defaults to the update revision").Required(false)).
+		Writes(modelsv1alpha1.ResourceDiff{}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, modelsv1alpha1.ResourceDiff{}))
+
+	// diff namespaced resources with a proposed update
+	ws.Route(ws.POST("/namespaces/{namespace}/{resources}/{name}/diff").
+		To(h.DiffResource).
+		Doc("Diff the live object with the result of a dry-run update with the proposed body, leaving out the status and server-set metadata").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.Common}).
+		Param(ws.PathParameter("namespace", "namespace of the Resource").Required(true)).
+		Param(ws.PathParameter("resources", "known values include clonesets, daemonsets, broadcastjobs, advancedcronjobs, uniteddeployments, workloadspreads, containerrecreaterequests, imagepulljobs, podprobemarkers, persistentpodstates, ephemeraljobs").Required(true)).
+		Param(ws.PathParameter("name", "name of the resource").Required(true)).
+		Writes(modelsv1alpha1.ResourceDiff{}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, modelsv1alpha1.ResourceDiff{}))
+
+	// set images of clonesets
+	ws.Route(ws.PATCH("/namespaces/{namespace}/clonesets/{name}/images").
+		To(h.SetCloneSetImages).
+		Doc("Set container images of the cloneset by container name, updating pods in place where possible").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.CloneSetType}).
+		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
+		Param(ws.PathParameter("name", "name of the cloneset").Required(true)).
+		Reads(modelsv1alpha1.SetCloneSetImages{}).
+		Writes(modelsv1alpha1.CloneSetImageUpdate{}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, modelsv1alpha1.CloneSetImageUpdate{}))
+
+	// get the rollout status of clonesets
+	ws.Route(ws.GET("/namespaces/{namespace}/clonesets/{name}/status").
+		To(h.GetCloneSetStatus).
+		Doc("Get the rollout status of the cloneset with the revision and in-place update state of each pod").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.CloneSetType}).
+		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
+		Param(ws.PathParameter("name", "name of the cloneset").Required(true)).
+		Writes(modelsv1alpha1.CloneSetRolloutStatus{}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, modelsv1alpha1.CloneSetRolloutStatus{}))
+
+	// get logs of clonesets
+	ws.Route(ws.GET("/namespaces/{namespace}/clonesets/{name}/log").
+		To(h.GetCloneSetLogs).
+		Doc("Get the logs of all pods of the cloneset, streamed in chunks with each line prefixed by the pod name").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.CloneSetType}).
+		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
+		Param(ws.PathParameter("name", "name of the cloneset").Required(true)).
+		Param(ws.QueryParameter("container", "container to show the logs of, defaults to the default container of the pod").Required(false)).
+		Param(ws.QueryParameter("tailLines", "number of lines from the end of the logs to show").Required(false).DataType("integer")).
+		Param(ws.QueryParameter("sinceSeconds", "show only logs newer than this many seconds").Required(false).DataType("integer")).
+		Param(ws.QueryParameter("previous", "show the logs of the previous terminated container").Required(false).DataType("boolean").DefaultValue("false")).
+		Param(ws.QueryParameter("follow", "stream the logs as they are written").Required(false).DataType("boolean").DefaultValue("false")).
+		Produces(restful.MIME_JSON, "text/plain").
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, ""))
+
 	// create clonesets
 	ws.Route(ws.POST("/namespaces/{namespace}/{resources}").
 		To(h.CreateResource).
 		Doc("create a cloneset").
 		Metadata(openapi.KeyOpenAPITags, []string{constants.CloneSetType}).
 		Param(ws.PathParameter("namespace", "namespace of the Resource").Required(true)).
-		Param(ws.PathParameter("resources", "known values include cloneset").Required(true)).
+		Param(ws.PathParameter("resources", "known values include clonesets, daemonsets, broadcastjobs, advancedcronjobs, uniteddeployments, workloadspreads, containerrecreaterequests, imagepulljobs, podprobemarkers, persistentpodstates, ephemeraljobs").Required(true)).
 		Writes(v1alpha1.CloneSet{}).
 		Produces(restful.MIME_JSON).
 		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
@@ -138,7 +532,7 @@ func registerCloneSetApi(ws *restful.WebService, h *Handler) {
 		Doc("create a cloneset or sidecarset").
 		Metadata(openapi.KeyOpenAPITags, []string{constants.CloneSetType}).
 		Param(ws.PathParameter("namespace", "namespace of the Resource").Required(true)).
-		Param(ws.PathParameter("resources", "known values include cloneset, sidecarset").Required(true)).
+		Param(ws.PathParameter("resources", "known values include clonesets, daemonsets, broadcastjobs, advancedcronjobs, uniteddeployments, workloadspreads, containerrecreaterequests, imagepulljobs, podprobemarkers, persistentpodstates, ephemeraljobs").Required(true)).
 		Param(ws.PathParameter("name", "name of the scaledobject").Required(true)).
 		Writes(v1alpha1.CloneSet{}).
 		Produces(restful.MIME_JSON).
@@ -151,7 +545,7 @@ func registerCloneSetApi(ws *restful.WebService, h *Handler) {
 		Doc("delete the specified cloneset or scaledjob").
 		Metadata(openapi.KeyOpenAPITags, []string{constants.CloneSetType}).
 		Param(ws.PathParameter("namespaces", "namespace of sidecarset").Required(true)).
-		Param(ws.PathParameter("resources", "known values include cloneset").Required(true)).
+		Param(ws.PathParameter("resources", "known values include clonesets, daemonsets, broadcastjobs, advancedcronjobs, uniteddeployments, workloadspreads, containerrecreaterequests, imagepulljobs, podprobemarkers, persistentpodstates, ephemeraljobs").Required(true)).
 		Param(ws.PathParameter(query.ParameterName, "the name of the resource").Required(true)).
 		Writes(serrors.None).
 		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
@@ -165,7 +559,7 @@ func registerSidecarSetApi(ws *restful.WebService, h *Handler) {
 		To(h.GetResource).
 		Doc("Get the sidecarset object").
 		Metadata(openapi.KeyOpenAPITags, []string{constants.SidecarSetType}).
-		Param(ws.PathParameter("resources", "known values include sidecarset").Required(true)).
+		Param(ws.PathParameter("resources", "known values include sidecarsets, nodeimages, resourcedistributions, nodepodprobes").Required(true)).
 		Param(ws.PathParameter("name", "name of sidecarset").Required(true)).
 		Writes(v1alpha1.SidecarSet{}).
 		Produces(restful.MIME_JSON).
@@ -177,7 +571,7 @@ func registerSidecarSetApi(ws *restful.WebService, h *Handler) {
 		To(h.CreateResource).
 		Doc("create a sidecarsets").
 		Metadata(openapi.KeyOpenAPITags, []string{constants.SidecarSetType}).
-		Param(ws.PathParameter("resources", "known values include sidecarsets").Required(true)).
+		Param(ws.PathParameter("resources", "known values include sidecarsets, resourcedistributions").Required(true)).
 		Writes(v1alpha1.SidecarSet{}).
 		Produces(restful.MIME_JSON).
 		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
@@ -188,19 +582,413 @@ func registerSidecarSetApi(ws *restful.WebService, h *Handler) {
 		To(h.UpdateResource).
 		Doc("create a sidecarset").
 		Metadata(openapi.KeyOpenAPITags, []string{constants.SidecarSetType}).
-		Param(ws.PathParameter("resources", "known values include sidecarset").Required(true)).
+		Param(ws.PathParameter("resources", "known values include sidecarsets, resourcedistributions").Required(true)).
 		Param(ws.PathParameter(query.ParameterName, "name of the sidecarset").Required(true)).
 		Writes(v1alpha1.SidecarSet{}).
 		Produces(restful.MIME_JSON).
 		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
 		Returns(http.StatusOK, api.StatusOK, v1alpha1.SidecarSet{}))
 
+	// list events of sidecarsets
+	ws.Route(ws.GET("/{resources}/{name}/events").
+		To(h.ListWorkloadEvents).
+		Doc("List the events of the sidecarset and its pods, merged and sorted by the time they last occurred").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.SidecarSetType}).
+		Param(ws.PathParameter("resources", "known values include sidecarsets, nodeimages, resourcedistributions, nodepodprobes").Required(true)).
+		Param(ws.PathParameter("name", "name of the sidecarset").Required(true)).
+		Writes(api.ListResult{Items: []interface{}{}}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, api.ListResult{Items: []interface{}{}}))
+
+	// diff revisions of sidecarsets
+	ws.Route(ws.GET("/sidecarsets/{name}/revisions/diff").
+		To(h.DiffSidecarSetRevisions).
+		Doc("Diff two revisions of the sidecarset, by default the latest revision with the one before it").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.SidecarSetType}).
+		Param(ws.PathParameter("name", "name of the sidecarset").Required(true)).
+		Param(ws.QueryParameter("from", "revision number to diff from, defaults to the revision before to").Required(false)).
+		Param(ws.QueryParameter("to", "revision number to diff to, defaults to the latest revision").Required(false)).
+		Writes(modelsv1alpha1.ResourceDiff{}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, modelsv1alpha1.ResourceDiff{}))
+
+	// diff cluster scoped resources with a proposed update
+	ws.Route(ws.POST("/{resources}/{name}/diff").
+		To(h.DiffResource).
+		Doc("Diff the live object with the result of a dry-run update with the proposed body, leaving out the status and server-set metadata").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.Common}).
+		Param(ws.PathParameter("resources", "known values include sidecarsets, resourcedistributions").Required(true)).
+		Param(ws.PathParameter("name", "name of the resource").Required(true)).
+		Writes(modelsv1alpha1.ResourceDiff{}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, modelsv1alpha1.ResourceDiff{}))
+
 	// delete sidecarsets
 	ws.Route(ws.DELETE("/{resources}/{name}").
 		To(h.DeleteResource).
 		Doc("delete the specified sidecarsets").
 		Metadata(openapi.KeyOpenAPITags, []string{constants.SidecarSetType}).
-		Param(ws.PathParameter("resources", "known values include sidecarset").Required(true)).
+		Param(ws.PathParameter("resources", "known values include sidecarsets, resourcedistributions").Required(true)).
+		Param(ws.PathParameter(query.ParameterName, "the name of the resource").Required(true)).
+		Writes(serrors.None).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, serrors.None))
+}
+
+func registerAdvancedCronJobApi(ws *restful.WebService, h *Handler) {
+
+	// list the jobs spawned by an advancedcronjob
+	ws.Route(ws.GET("/namespaces/{namespace}/advancedcronjobs/{name}/jobs").
+		To(h.ListAdvancedCronJobRuns).
+		Doc("List the Jobs and BroadcastJobs spawned by the advancedcronjob with their completion status").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.AdvancedCronJobType}).
+		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
+		Param(ws.PathParameter("name", "name of the advancedcronjob").Required(true)).
+		Writes(api.ListResult{Items: []interface{}{}}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, api.ListResult{Items: []interface{}{}}))
+}
+
+func registerUnitedDeploymentApi(ws *restful.WebService, h *Handler) {
+
+	// list the subsets of a uniteddeployment
+	ws.Route(ws.GET("/namespaces/{namespace}/uniteddeployments/{name}/subsets").
+		To(h.ListUnitedDeploymentSubsets).
+		Doc("List the desired, ready and updated replicas of every subset of the uniteddeployment").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.UnitedDeploymentType}).
+		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
+		Param(ws.PathParameter("name", "name of the uniteddeployment").Required(true)).
+		Writes(api.ListResult{Items: []interface{}{}}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, api.ListResult{Items: []interface{}{}}))
+}
+
+func registerResourceDistributionApi(ws *restful.WebService, h *Handler) {
+
+	// list the target namespaces of a resourcedistribution
+	ws.Route(ws.GET("/resourcedistributions/{name}/targets").
+		To(h.ListResourceDistributionTargets).
+		Doc("List the target namespaces of the resourcedistribution and whether distribution succeeded or failed").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.ResourceDistributionType}).
+		Param(ws.PathParameter("name", "name of the resourcedistribution").Required(true)).
+		Writes(api.ListResult{Items: []interface{}{}}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, api.ListResult{Items: []interface{}{}}))
+}
+
+func registerPodProbeMarkerApi(ws *restful.WebService, h *Handler) {
+
+	// list the pods selected by a podprobemarker with their probe results
+	ws.Route(ws.GET("/namespaces/{namespace}/podprobemarkers/{name}/pods").
+		To(h.ListPodProbeResults).
+		Doc("List the pods selected by the podprobemarker with the probe results reported by their nodepodprobes").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.PodProbeMarkerType}).
+		Param(ws.PathParameter("namespace", "namespace of the podprobemarker").Required(true)).
+		Param(ws.PathParameter("name", "name of the podprobemarker").Required(true)).
+		Writes(api.ListResult{Items: []interface{}{}}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, api.ListResult{Items: []interface{}{}}))
+}
+
+func registerEphemeralJobApi(ws *restful.WebService, h *Handler) {
+
+	// list the pods of an ephemeraljob with the states of their ephemeral containers
+	ws.Route(ws.GET("/namespaces/{namespace}/ephemeraljobs/{name}/pods").
+		To(h.ListEphemeralJobPods).
+		Doc("List the pods selected by the ephemeraljob with the states of the ephemeral containers injected into them").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.EphemeralJobType}).
+		Param(ws.PathParameter("namespace", "namespace of the ephemeraljob").Required(true)).
+		Param(ws.PathParameter("name", "name of the ephemeraljob").Required(true)).
+		Writes(api.ListResult{Items: []interface{}{}}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, api.ListResult{Items: []interface{}{}}))
+}
+
+func registerNativeApi(ws *restful.WebService, h *Handler) {
+
+	// list native workloads in all namespaces
+	ws.Route(ws.GET("/{resources}").
+		To(h.ListNativeResource).
+		Doc("List the native deployment or statefulset object in all namespace").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.Common}).
+		Param(ws.PathParameter("resources", "known values include deployments, statefulsets").Required(true)).
+		Param(ws.QueryParameter(query.ParameterPage, "page").Required(false).DataFormat("page=%d").DefaultValue("page=1")).
+		Param(ws.QueryParameter(query.ParameterLimit, "limit").Required(false)).
+		Param(ws.QueryParameter(query.ParameterAscending, "sort parameters, e.g. ascending=false").Required(false).DefaultValue("ascending=false")).
+		Param(ws.QueryParameter(query.ParameterOrderBy, "sort parameters, e.g. orderBy=createTime")).
+		Writes(api.ListResult{Items: []interface{}{}}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, api.ListResult{Items: []interface{}{}}))
+
+	// list native workloads in a namespace
+	ws.Route(ws.GET("/namespaces/{namespace}/{resources}").
+		To(h.ListNativeResource).
+		Doc("List the native deployment or statefulset object in the specified namespace").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.Common}).
+		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
+		Param(ws.PathParameter("resources", "known values include deployments, statefulsets").Required(true)).
+		Param(ws.QueryParameter(query.ParameterPage, "page").Required(false).DataFormat("page=%d").DefaultValue("page=1")).
+		Param(ws.QueryParameter(query.ParameterLimit, "limit").Required(false)).
+		Param(ws.QueryParameter(query.ParameterAscending, "sort parameters, e.g. ascending=false").Required(false).DefaultValue("ascending=false")).
+		Param(ws.QueryParameter(query.ParameterOrderBy, "sort parameters, e.g. orderBy=createTime")).
+		Writes(api.ListResult{Items: []interface{}{}}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, api.ListResult{Items: []interface{}{}}))
+
+	// get native workloads
+	ws.Route(ws.GET("/namespaces/{namespace}/{resources}/{name}").
+		To(h.GetNativeResource).
+		Doc("Get the native deployment or statefulset object in the specified namespace").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.Common}).
+		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
+		Param(ws.PathParameter("resources", "known values include deployments, statefulsets").Required(true)).
+		Param(ws.PathParameter("name", "name of the workload").Required(true)).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, nil))
+
+	// migrate a deployment to a cloneset
+	ws.Route(ws.POST("/namespaces/{namespace}/deployments/{name}/migration").
+		To(h.MigrateDeployment).
+		Doc("Generate a cloneset equivalent to the deployment and create it unless dryRun is set, optionally moving the replicas over step by step").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.DeploymentType}).
+		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
+		Param(ws.PathParameter("name", "name of the deployment").Required(true)).
+		Reads(modelsv1alpha1.MigrateDeployment{}).
+		Writes(modelsv1alpha1.DeploymentMigration{}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, modelsv1alpha1.DeploymentMigration{}))
+
+	// get the progress of a deployment migration
+	ws.Route(ws.GET("/namespaces/{namespace}/deployments/{name}/migration").
+		To(h.GetDeploymentMigration).
+		Doc("Get the progress of the latest migration of the deployment to a cloneset").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.DeploymentType}).
+		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
+		Param(ws.PathParameter("name", "name of the deployment").Required(true)).
+		Writes(modelsv1alpha1.DeploymentMigrationStatus{}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, modelsv1alpha1.DeploymentMigrationStatus{}))
+}
+
+func registerStatefulSetApi(ws *restful.WebService, h *Handler) {
+
+	// list statefulsets in all namespaces
+	ws.Route(ws.GET("/{resources}").
+		To(h.ListResource).
+		Doc("List the advanced statefulset object in all namespace").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.StatefulSetType}).
+		Param(ws.PathParameter("resources", "known values include statefulsets").Required(true)).
+		Param(ws.QueryParameter(query.ParameterPage, "page").Required(false).DataFormat("page=%d").DefaultValue("page=1")).
+		Param(ws.QueryParameter(query.ParameterLimit, "limit").Required(false)).
+		Param(ws.QueryParameter(query.ParameterAscending, "sort parameters, e.g. ascending=false").Required(false).DefaultValue("ascending=false")).
+		Param(ws.QueryParameter(query.ParameterOrderBy, "sort parameters, e.g. orderBy=createTime")).
+		Writes(api.ListResult{Items: []interface{}{}}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, api.ListResult{Items: []interface{}{}}))
+
+	// list statefulsets in a namespace
+	ws.Route(ws.GET("/namespaces/{namespace}/{resources}").
+		To(h.ListResource).
+		Doc("List the advanced statefulset object in the specified namespace").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.StatefulSetType}).
+		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
+		Param(ws.PathParameter("resources", "known values include statefulsets").Required(true)).
+		Param(ws.QueryParameter(query.ParameterPage, "page").Required(false).DataFormat("page=%d").DefaultValue("page=1")).
+		Param(ws.QueryParameter(query.ParameterLimit, "limit").Required(false)).
+		Param(ws.QueryParameter(query.ParameterAscending, "sort parameters, e.g. ascending=false").Required(false).DefaultValue("ascending=false")).
+		Param(ws.QueryParameter(query.ParameterOrderBy, "sort parameters, e.g. orderBy=createTime")).
+		Writes(api.ListResult{Items: []interface{}{}}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, api.ListResult{Items: []interface{}{}}))
+
+	// get statefulsets
+	ws.Route(ws.GET("/namespaces/{namespace}/{resources}/{name}").
+		To(h.GetResource).
+		Doc("Get the advanced statefulset object in the specified namespace").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.StatefulSetType}).
+		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
+		Param(ws.PathParameter("resources", "known values include statefulsets").Required(true)).
+		Param(ws.PathParameter("name", "name of the statefulset").Required(true)).
+		Writes(v1beta1.StatefulSet{}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, v1beta1.StatefulSet{}))
+
+	// list pods of statefulsets
+	ws.Route(ws.GET("/namespaces/{namespace}/{resources}/{name}/pods").
+		To(h.ListWorkloadPods).
+		Doc("List the pods of the advanced statefulset in the specified namespace").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.StatefulSetType}).
+		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
+		Param(ws.PathParameter("resources", "known values include statefulsets").Required(true)).
+		Param(ws.PathParameter("name", "name of the statefulset").Required(true)).
+		Writes(api.ListResult{Items: []interface{}{}}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, api.ListResult{Items: []interface{}{}}))
+
+	// complete the lifecycle hook of a pod of statefulsets
+	ws.Route(ws.POST("/namespaces/{namespace}/{resources}/{name}/pods/{pod}/lifecycle/complete").
+		To(h.CompletePodLifecycleHook).
+		Doc("Remove the finalizers and labels of the preDelete or inPlaceUpdate hook the pod waits for, so it goes on being deleted or updated").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.StatefulSetType}).
+		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
+		Param(ws.PathParameter("resources", "known values include statefulsets").Required(true)).
+		Param(ws.PathParameter("name", "name of the statefulset").Required(true)).
+		Param(ws.PathParameter("pod", "name of the pod").Required(true)).
+		Writes(modelsv1alpha1.LifecyclePod{}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, modelsv1alpha1.LifecyclePod{}))
+
+	// list events of statefulsets
+	ws.Route(ws.GET("/namespaces/{namespace}/{resources}/{name}/events").
+		To(h.ListWorkloadEvents).
+		Doc("List the events of the statefulset and its pods, merged and sorted by the time they last occurred").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.StatefulSetType}).
+		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
+		Param(ws.PathParameter("resources", "known values include statefulsets").Required(true)).
+		Param(ws.PathParameter("name", "name of the statefulset").Required(true)).
+		Writes(api.ListResult{Items: []interface{}{}}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, api.ListResult{Items: []interface{}{}}))
+
+	// create statefulsets
+	ws.Route(ws.POST("/namespaces/{namespace}/{resources}").
+		To(h.CreateResource).
+		Doc("create an advanced statefulset").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.StatefulSetType}).
+		Param(ws.PathParameter("namespace", "namespace of the Resource").Required(true)).
+		Param(ws.PathParameter("resources", "known values include statefulsets").Required(true)).
+		Reads(v1beta1.StatefulSet{}).
+		Writes(v1beta1.StatefulSet{}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, v1beta1.StatefulSet{}))
+
+	// update statefulsets
+	ws.Route(ws.PUT("/namespaces/{namespace}/{resources}/{name}").
+		To(h.UpdateResource).
+		Doc("update an advanced statefulset").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.StatefulSetType}).
+		Param(ws.PathParameter("namespace", "namespace of the Resource").Required(true)).
+		Param(ws.PathParameter("resources", "known values include statefulsets").Required(true)).
+		Param(ws.PathParameter("name", "name of the statefulset").Required(true)).
+		Reads(v1beta1.StatefulSet{}).
+		Writes(v1beta1.StatefulSet{}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, v1beta1.StatefulSet{}))
+
+	// delete statefulsets
+	ws.Route(ws.DELETE("/namespaces/{namespace}/{resources}/{name}").
+		To(h.DeleteResource).
+		Doc("delete the specified advanced statefulset").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.StatefulSetType}).
+		Param(ws.PathParameter("namespace", "namespace of the Resource").Required(true)).
+		Param(ws.PathParameter("resources", "known values include statefulsets").Required(true)).
+		Param(ws.PathParameter(query.ParameterName, "the name of the resource").Required(true)).
+		Writes(serrors.None).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, serrors.None))
+}
+
+func registerPodUnavailableBudgetApi(ws *restful.WebService, h *Handler) {
+
+	// list podunavailablebudgets in all namespaces
+	ws.Route(ws.GET("/{resources}").
+		To(h.ListResource).
+		Doc("List the podunavailablebudget object in all namespace").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.PodUnavailableBudgetType}).
+		Param(ws.PathParameter("resources", "known values include podunavailablebudgets").Required(true)).
+		Param(ws.QueryParameter(query.ParameterPage, "page").Required(false).DataFormat("page=%d").DefaultValue("page=1")).
+		Param(ws.QueryParameter(query.ParameterLimit, "limit").Required(false)).
+		Param(ws.QueryParameter(query.ParameterAscending, "sort parameters, e.g. ascending=false").Required(false).DefaultValue("ascending=false")).
+		Param(ws.QueryParameter(query.ParameterOrderBy, "sort parameters, e.g. orderBy=createTime")).
+		Writes(api.ListResult{Items: []interface{}{}}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, api.ListResult{Items: []interface{}{}}))
+
+	// list podunavailablebudgets in a namespace
+	ws.Route(ws.GET("/namespaces/{namespace}/{resources}").
+		To(h.ListResource).
+		Doc("List the podunavailablebudget object in the specified namespace").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.PodUnavailableBudgetType}).
+		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
+		Param(ws.PathParameter("resources", "known values include podunavailablebudgets").Required(true)).
+		Param(ws.QueryParameter(query.ParameterPage, "page").Required(false).DataFormat("page=%d").DefaultValue("page=1")).
+		Param(ws.QueryParameter(query.ParameterLimit, "limit").Required(false)).
+		Param(ws.QueryParameter(query.ParameterAscending, "sort parameters, e.g. ascending=false").Required(false).DefaultValue("ascending=false")).
+		Param(ws.QueryParameter(query.ParameterOrderBy, "sort parameters, e.g. orderBy=createTime")).
+		Writes(api.ListResult{Items: []interface{}{}}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, api.ListResult{Items: []interface{}{}}))
+
+	// get podunavailablebudgets
+	ws.Route(ws.GET("/namespaces/{namespace}/{resources}/{name}").
+		To(h.GetResource).
+		Doc("Get the podunavailablebudget object in the specified namespace").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.PodUnavailableBudgetType}).
+		Param(ws.PathParameter("namespace", "name of the namespace").Required(true)).
+		Param(ws.PathParameter("resources", "known values include podunavailablebudgets").Required(true)).
+		Param(ws.PathParameter("name", "name of the podunavailablebudget").Required(true)).
+		Writes(policyv1alpha1.PodUnavailableBudget{}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, policyv1alpha1.PodUnavailableBudget{}))
+
+	// create podunavailablebudgets
+	ws.Route(ws.POST("/namespaces/{namespace}/{resources}").
+		To(h.CreateResource).
+		Doc("create a podunavailablebudget").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.PodUnavailableBudgetType}).
+		Param(ws.PathParameter("namespace", "namespace of the Resource").Required(true)).
+		Param(ws.PathParameter("resources", "known values include podunavailablebudgets").Required(true)).
+		Reads(policyv1alpha1.PodUnavailableBudget{}).
+		Writes(policyv1alpha1.PodUnavailableBudget{}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, policyv1alpha1.PodUnavailableBudget{}))
+
+	// update podunavailablebudgets
+	ws.Route(ws.PUT("/namespaces/{namespace}/{resources}/{name}").
+		To(h.UpdateResource).
+		Doc("update a podunavailablebudget").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.PodUnavailableBudgetType}).
+		Param(ws.PathParameter("namespace", "namespace of the Resource").Required(true)).
+		Param(ws.PathParameter("resources", "known values include podunavailablebudgets").Required(true)).
+		Param(ws.PathParameter("name", "name of the podunavailablebudget").Required(true)).
+		Reads(policyv1alpha1.PodUnavailableBudget{}).
+		Writes(policyv1alpha1.PodUnavailableBudget{}).
+		Produces(restful.MIME_JSON).
+		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
+		Returns(http.StatusOK, api.StatusOK, policyv1alpha1.PodUnavailableBudget{}))
+
+	// delete podunavailablebudgets
+	ws.Route(ws.DELETE("/namespaces/{namespace}/{resources}/{name}").
+		To(h.DeleteResource).
+		Doc("delete the specified podunavailablebudget").
+		Metadata(openapi.KeyOpenAPITags, []string{constants.PodUnavailableBudgetType}).
+		Param(ws.PathParameter("namespace", "namespace of the Resource").Required(true)).
+		Param(ws.PathParameter("resources", "known values include podunavailablebudgets").Required(true)).
 		Param(ws.PathParameter(query.ParameterName, "the name of the resource").Required(true)).
 		Writes(serrors.None).
 		ReturnsError(http.StatusInternalServerError, api.StatusError, api.ErrorMessage{}).
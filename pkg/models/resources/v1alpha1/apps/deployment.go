@@ -0,0 +1,97 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apps
+
+import (
+	"strings"
+
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/api"
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/apiserver/query"
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/models/resources/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sinformers "k8s.io/client-go/informers"
+)
+
+const (
+	statusStopped  = "stopped"
+	statusRunning  = "running"
+	statusUpdating = "updating"
+)
+
+type DeploymentObjectGetter struct {
+	informer k8sinformers.SharedInformerFactory
+}
+
+func NewDeploymentObjectGetter(informer k8sinformers.SharedInformerFactory) v1alpha1.Interface {
+	return &DeploymentObjectGetter{informer: informer}
+}
+
+func (s *DeploymentObjectGetter) Get(namespace, name string) (runtime.Object, error) {
+	return s.informer.Apps().V1().Deployments().Lister().Deployments(namespace).Get(name)
+}
+
+func (s *DeploymentObjectGetter) List(namespace string, query *query.Query) (*api.ListResult, error) {
+	objs, err := s.informer.Apps().V1().Deployments().Lister().Deployments(namespace).List(query.Selector())
+	if err != nil {
+		return nil, err
+	}
+
+	var result []runtime.Object
+	for _, obj := range objs {
+		result = append(result, obj)
+	}
+
+	return v1alpha1.DefaultList(result, query, s.compare, s.filter), nil
+}
+
+func (s *DeploymentObjectGetter) compare(left runtime.Object, right runtime.Object, field query.Field) bool {
+	leftObj, ok := left.(*appsv1.Deployment)
+	if !ok {
+		return false
+	}
+
+	rightObj, ok := right.(*appsv1.Deployment)
+	if !ok {
+		return false
+	}
+
+	return v1alpha1.DefaultObjectMetaCompare(leftObj.ObjectMeta, rightObj.ObjectMeta, field)
+}
+
+func (s *DeploymentObjectGetter) filter(obj runtime.Object, filter query.Filter) bool {
+	deployment, ok := obj.(*appsv1.Deployment)
+	if !ok {
+		return false
+	}
+	switch filter.Field {
+	case query.FieldStatus:
+		return strings.Compare(deploymentStatus(deployment.Status), string(filter.Value)) == 0
+	default:
+		return v1alpha1.DefaultObjectMetaFilter(deployment.ObjectMeta, filter)
+	}
+}
+
+func deploymentStatus(status appsv1.DeploymentStatus) string {
+	if status.Replicas == 0 && status.ReadyReplicas == 0 {
+		return statusStopped
+	} else if status.ReadyReplicas == status.Replicas {
+		return statusRunning
+	} else {
+		return statusUpdating
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apps
+
+import (
+	"strings"
+
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/api"
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/apiserver/query"
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/models/resources/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sinformers "k8s.io/client-go/informers"
+)
+
+type StatefulSetObjectGetter struct {
+	informer k8sinformers.SharedInformerFactory
+}
+
+func NewStatefulSetObjectGetter(informer k8sinformers.SharedInformerFactory) v1alpha1.Interface {
+	return &StatefulSetObjectGetter{informer: informer}
+}
+
+func (s *StatefulSetObjectGetter) Get(namespace, name string) (runtime.Object, error) {
+	return s.informer.Apps().V1().StatefulSets().Lister().StatefulSets(namespace).Get(name)
+}
+
+func (s *StatefulSetObjectGetter) List(namespace string, query *query.Query) (*api.ListResult, error) {
+	objs, err := s.informer.Apps().V1().StatefulSets().Lister().StatefulSets(namespace).List(query.Selector())
+	if err != nil {
+		return nil, err
+	}
+
+	var result []runtime.Object
+	for _, obj := range objs {
+		result = append(result, obj)
+	}
+
+	return v1alpha1.DefaultList(result, query, s.compare, s.filter), nil
+}
+
+func (s *StatefulSetObjectGetter) compare(left runtime.Object, right runtime.Object, field query.Field) bool {
+	leftObj, ok := left.(*appsv1.StatefulSet)
+	if !ok {
+		return false
+	}
+
+	rightObj, ok := right.(*appsv1.StatefulSet)
+	if !ok {
+		return false
+	}
+
+	return v1alpha1.DefaultObjectMetaCompare(leftObj.ObjectMeta, rightObj.ObjectMeta, field)
+}
+
+func (s *StatefulSetObjectGetter) filter(obj runtime.Object, filter query.Filter) bool {
+	statefulSet, ok := obj.(*appsv1.StatefulSet)
+	if !ok {
+		return false
+	}
+	switch filter.Field {
+	case query.FieldStatus:
+		return strings.Compare(statefulSetStatus(statefulSet.Status), string(filter.Value)) == 0
+	default:
+		return v1alpha1.DefaultObjectMetaFilter(statefulSet.ObjectMeta, filter)
+	}
+}
+
+func statefulSetStatus(status appsv1.StatefulSetStatus) string {
+	if status.Replicas == 0 && status.ReadyReplicas == 0 {
+		return statusStopped
+	} else if status.ReadyReplicas == status.Replicas {
+		return statusRunning
+	} else {
+		return statusUpdating
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kruise
+
+import (
+	"testing"
+
+	kruisev1alpha1 "github.com/openkruise/kruise-api/apps/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDaemonSetStatus(t *testing.T) {
+	paused := true
+	tests := []struct {
+		name      string
+		daemonSet *kruisev1alpha1.DaemonSet
+		want      string
+	}{
+		{
+			name: "all pods updated and ready",
+			daemonSet: &kruisev1alpha1.DaemonSet{Status: kruisev1alpha1.DaemonSetStatus{
+				DesiredNumberScheduled: 3, NumberReady: 3, UpdatedNumberScheduled: 3,
+			}},
+			want: statusRunning,
+		},
+		{
+			name: "pods not yet updated",
+			daemonSet: &kruisev1alpha1.DaemonSet{Status: kruisev1alpha1.DaemonSetStatus{
+				DesiredNumberScheduled: 3, NumberReady: 3, UpdatedNumberScheduled: 1,
+			}},
+			want: statusRolling,
+		},
+		{
+			name: "pods not yet ready",
+			daemonSet: &kruisev1alpha1.DaemonSet{Status: kruisev1alpha1.DaemonSetStatus{
+				DesiredNumberScheduled: 3, NumberReady: 2, UpdatedNumberScheduled: 3,
+			}},
+			want: statusRolling,
+		},
+		{
+			name: "rolling update paused",
+			daemonSet: &kruisev1alpha1.DaemonSet{
+				Spec: kruisev1alpha1.DaemonSetSpec{UpdateStrategy: kruisev1alpha1.DaemonSetUpdateStrategy{
+					RollingUpdate: &kruisev1alpha1.RollingUpdateDaemonSet{Paused: &paused},
+				}},
+				Status: kruisev1alpha1.DaemonSetStatus{
+					DesiredNumberScheduled: 3, NumberReady: 3, UpdatedNumberScheduled: 1,
+				},
+			},
+			want: statusPaused,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, daemonSetStatus(tt.daemonSet))
+		})
+	}
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kruise
+
+import (
+	"strings"
+
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/api"
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/apiserver/query"
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/models/resources/v1alpha1"
+	kruisev1alpha1 "github.com/openkruise/kruise-api/apps/v1alpha1"
+	kruiseinformer "github.com/openkruise/kruise-api/client/informers/externalversions"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type EphemeralJobObjectGetter struct {
+	informer kruiseinformer.SharedInformerFactory
+}
+
+func NewEphemeralJobObjectGetter(informer kruiseinformer.SharedInformerFactory) v1alpha1.Interface {
+	return &EphemeralJobObjectGetter{informer: informer}
+}
+
+func (s *EphemeralJobObjectGetter) Get(namespace, name string) (runtime.Object, error) {
+	return s.informer.Apps().V1alpha1().EphemeralJobs().Lister().EphemeralJobs(namespace).Get(name)
+}
+
+func (s *EphemeralJobObjectGetter) List(namespace string, query *query.Query) (*api.ListResult, error) {
+	objs, err := s.informer.Apps().V1alpha1().EphemeralJobs().Lister().EphemeralJobs(namespace).List(query.Selector())
+	if err != nil {
+		return nil, err
+	}
+
+	var result []runtime.Object
+	for _, obj := range objs {
+		result = append(result, obj)
+	}
+
+	return v1alpha1.DefaultList(result, query, s.compare, s.filter), nil
+}
+
+func (s *EphemeralJobObjectGetter) compare(left runtime.Object, right runtime.Object, field query.Field) bool {
+	leftObj, ok := left.(*kruisev1alpha1.EphemeralJob)
+	if !ok {
+		return false
+	}
+
+	rightObj, ok := right.(*kruisev1alpha1.EphemeralJob)
+	if !ok {
+		return false
+	}
+
+	return v1alpha1.DefaultObjectMetaCompare(leftObj.ObjectMeta, rightObj.ObjectMeta, field)
+}
+
+func (s *EphemeralJobObjectGetter) filter(obj runtime.Object, filter query.Filter) bool {
+	ephemeralJob, ok := obj.(*kruisev1alpha1.EphemeralJob)
+	if !ok {
+		return false
+	}
+	switch filter.Field {
+	case query.FieldStatus:
+		return strings.Compare(string(ephemeralJob.Status.Phase), string(filter.Value)) == 0
+	default:
+		return v1alpha1.DefaultObjectMetaFilter(ephemeralJob.ObjectMeta, filter)
+	}
+}
@@ -27,9 +27,14 @@ limitations under the License.
 */
 
 const (
-	statusStopped  = "stopped"
-	statusRunning  = "running"
-	statusUpdating = "updating"
+	statusStopped   = "stopped"
+	statusRunning   = "running"
+	statusUpdating  = "updating"
+	statusRolling   = "rolling"
+	statusPaused    = "paused"
+	statusWaiting   = "waiting"
+	statusCompleted = "completed"
+	statusFailed    = "failed"
 )
 
 type CloneSetObjectGetter struct {
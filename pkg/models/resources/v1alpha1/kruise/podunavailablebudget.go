@@ -0,0 +1,74 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kruise
+
+import (
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/api"
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/apiserver/query"
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/models/resources/v1alpha1"
+	kruiseinformer "github.com/openkruise/kruise-api/client/informers/externalversions"
+	kruisepolicyv1alpha1 "github.com/openkruise/kruise-api/policy/v1alpha1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type PodUnavailableBudgetObjectGetter struct {
+	informer kruiseinformer.SharedInformerFactory
+}
+
+func NewPodUnavailableBudgetObjectGetter(informer kruiseinformer.SharedInformerFactory) v1alpha1.Interface {
+	return &PodUnavailableBudgetObjectGetter{informer: informer}
+}
+
+func (s *PodUnavailableBudgetObjectGetter) Get(namespace, name string) (runtime.Object, error) {
+	return s.informer.Policy().V1alpha1().PodUnavailableBudgets().Lister().PodUnavailableBudgets(namespace).Get(name)
+}
+
+func (s *PodUnavailableBudgetObjectGetter) List(namespace string, query *query.Query) (*api.ListResult, error) {
+	objs, err := s.informer.Policy().V1alpha1().PodUnavailableBudgets().Lister().PodUnavailableBudgets(namespace).List(query.Selector())
+	if err != nil {
+		return nil, err
+	}
+
+	var result []runtime.Object
+	for _, obj := range objs {
+		result = append(result, obj)
+	}
+
+	return v1alpha1.DefaultList(result, query, s.compare, s.filter), nil
+}
+
+func (s *PodUnavailableBudgetObjectGetter) compare(left runtime.Object, right runtime.Object, field query.Field) bool {
+	leftObj, ok := left.(*kruisepolicyv1alpha1.PodUnavailableBudget)
+	if !ok {
+		return false
+	}
+
+	rightObj, ok := right.(*kruisepolicyv1alpha1.PodUnavailableBudget)
+	if !ok {
+		return false
+	}
+
+	return v1alpha1.DefaultObjectMetaCompare(leftObj.ObjectMeta, rightObj.ObjectMeta, field)
+}
+
+func (s *PodUnavailableBudgetObjectGetter) filter(obj runtime.Object, filter query.Filter) bool {
+	podUnavailableBudget, ok := obj.(*kruisepolicyv1alpha1.PodUnavailableBudget)
+	if !ok {
+		return false
+	}
+	return v1alpha1.DefaultObjectMetaFilter(podUnavailableBudget.ObjectMeta, filter)
+}
@@ -19,8 +19,10 @@ package resource
 import (
 	"errors"
 	"github.com/Gentleelephant/EnhancementWorkload/pkg/constants"
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/models/resources/v1alpha1/apps"
 	"github.com/Gentleelephant/EnhancementWorkload/pkg/models/resources/v1alpha1/kruise"
 
+	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
@@ -30,6 +32,8 @@ import (
 	"github.com/Gentleelephant/EnhancementWorkload/pkg/informers"
 	"github.com/Gentleelephant/EnhancementWorkload/pkg/models/resources/v1alpha1"
 	kruisev1alpha1 "github.com/openkruise/kruise-api/apps/v1alpha1"
+	kruisev1beta1 "github.com/openkruise/kruise-api/apps/v1beta1"
+	kruisepolicyv1alpha1 "github.com/openkruise/kruise-api/policy/v1alpha1"
 )
 
 var ErrResourceNotSupported = errors.New("resource is not supported")
@@ -45,30 +49,58 @@ func NewResourceGetter(factory informers.InformerFactory, cache cache.Cache) *Re
 
 	namespacedResourceGetters[kruisev1alpha1.SchemeGroupVersion.WithResource(constants.CloneSetType)] = kruise.NewCloneSetObjectGetter(factory.KruiseInformerFactory())
 	clusterResourceGetters[kruisev1alpha1.SchemeGroupVersion.WithResource(constants.SidecarSetType)] = kruise.NewSidecarSetGetter(factory.KruiseInformerFactory())
+	namespacedResourceGetters[kruisev1alpha1.SchemeGroupVersion.WithResource(constants.DaemonSetType)] = kruise.NewDaemonSetObjectGetter(factory.KruiseInformerFactory())
+	namespacedResourceGetters[kruisev1alpha1.SchemeGroupVersion.WithResource(constants.BroadcastJobType)] = kruise.NewBroadcastJobObjectGetter(factory.KruiseInformerFactory())
+	namespacedResourceGetters[kruisev1alpha1.SchemeGroupVersion.WithResource(constants.AdvancedCronJobType)] = kruise.NewAdvancedCronJobObjectGetter(factory.KruiseInformerFactory())
+	namespacedResourceGetters[kruisev1alpha1.SchemeGroupVersion.WithResource(constants.UnitedDeploymentType)] = kruise.NewUnitedDeploymentObjectGetter(factory.KruiseInformerFactory())
+	namespacedResourceGetters[kruisev1alpha1.SchemeGroupVersion.WithResource(constants.WorkloadSpreadType)] = kruise.NewWorkloadSpreadObjectGetter(factory.KruiseInformerFactory())
+	namespacedResourceGetters[kruisev1alpha1.SchemeGroupVersion.WithResource(constants.ContainerRecreateRequestType)] = kruise.NewContainerRecreateRequestObjectGetter(factory.KruiseInformerFactory())
+	namespacedResourceGetters[kruisev1alpha1.SchemeGroupVersion.WithResource(constants.ImagePullJobType)] = kruise.NewImagePullJobObjectGetter(factory.KruiseInformerFactory())
+	clusterResourceGetters[kruisev1alpha1.SchemeGroupVersion.WithResource(constants.NodeImageType)] = kruise.NewNodeImageObjectGetter(factory.KruiseInformerFactory())
+	clusterResourceGetters[kruisev1alpha1.SchemeGroupVersion.WithResource(constants.ResourceDistributionType)] = kruise.NewResourceDistributionObjectGetter(factory.KruiseInformerFactory())
+	namespacedResourceGetters[kruisev1alpha1.SchemeGroupVersion.WithResource(constants.PodProbeMarkerType)] = kruise.NewPodProbeMarkerObjectGetter(factory.KruiseInformerFactory())
+	clusterResourceGetters[kruisev1alpha1.SchemeGroupVersion.WithResource(constants.NodePodProbeType)] = kruise.NewNodePodProbeObjectGetter(factory.KruiseInformerFactory())
+	namespacedResourceGetters[kruisev1alpha1.SchemeGroupVersion.WithResource(constants.PersistentPodStateType)] = kruise.NewPersistentPodStateObjectGetter(factory.KruiseInformerFactory())
+	namespacedResourceGetters[kruisev1alpha1.SchemeGroupVersion.WithResource(constants.EphemeralJobType)] = kruise.NewEphemeralJobObjectGetter(factory.KruiseInformerFactory())
+	namespacedResourceGetters[kruisev1beta1.SchemeGroupVersion.WithResource(constants.StatefulSetType)] = kruise.NewStatefulSetObjectGetter(factory.KruiseInformerFactory())
+	namespacedResourceGetters[kruisepolicyv1alpha1.SchemeGroupVersion.WithResource(constants.PodUnavailableBudgetType)] = kruise.NewPodUnavailableBudgetObjectGetter(factory.KruiseInformerFactory())
+	namespacedResourceGetters[appsv1.SchemeGroupVersion.WithResource(constants.DeploymentType)] = apps.NewDeploymentObjectGetter(factory.KubernetesSharedInformerFactory())
+	namespacedResourceGetters[appsv1.SchemeGroupVersion.WithResource(constants.StatefulSetType)] = apps.NewStatefulSetObjectGetter(factory.KubernetesSharedInformerFactory())
 	return &ResourceGetter{
 		namespacedResourceGetters: namespacedResourceGetters,
 		clusterResourceGetters:    clusterResourceGetters,
 	}
 }
 
-// TryResource will retrieve a getter with resource name, it doesn't guarantee find resource with correct group version
-// need to refactor this use schema.GroupVersionResource
+// TryResource will retrieve a getter with resource name, it doesn't guarantee find resource with correct group version.
+// Native apps/v1 resources share their names with kruise ones, so they are skipped here and
+// can only be retrieved with TryGroupVersionResource.
 func (r *ResourceGetter) TryResource(clusterScope bool, resource string) v1alpha1.Interface {
 	if clusterScope {
 		for k, v := range r.clusterResourceGetters {
-			if k.Resource == resource {
+			if k.Resource == resource && k.Group != appsv1.GroupName {
 				return v
 			}
 		}
 	}
 	for k, v := range r.namespacedResourceGetters {
-		if k.Resource == resource {
+		if k.Resource == resource && k.Group != appsv1.GroupName {
 			return v
 		}
 	}
 	return nil
 }
 
+// TryGroupVersionResource will retrieve a getter with the exact group version resource.
+func (r *ResourceGetter) TryGroupVersionResource(clusterScope bool, gvr schema.GroupVersionResource) v1alpha1.Interface {
+	if clusterScope {
+		if getter, ok := r.clusterResourceGetters[gvr]; ok {
+			return getter
+		}
+	}
+	return r.namespacedResourceGetters[gvr]
+}
+
 func (r *ResourceGetter) Get(resource, namespace, name string) (runtime.Object, error) {
 	clusterScope := namespace == ""
 	getter := r.TryResource(clusterScope, resource)
@@ -86,3 +118,21 @@ func (r *ResourceGetter) List(resource, namespace string, query *query.Query) (*
 	}
 	return getter.List(namespace, query)
 }
+
+func (r *ResourceGetter) GetWithGroupVersion(gvr schema.GroupVersionResource, namespace, name string) (runtime.Object, error) {
+	clusterScope := namespace == ""
+	getter := r.TryGroupVersionResource(clusterScope, gvr)
+	if getter == nil {
+		return nil, ErrResourceNotSupported
+	}
+	return getter.Get(namespace, name)
+}
+
+func (r *ResourceGetter) ListWithGroupVersion(gvr schema.GroupVersionResource, namespace string, query *query.Query) (*api.ListResult, error) {
+	clusterScope := namespace == ""
+	getter := r.TryGroupVersionResource(clusterScope, gvr)
+	if getter == nil {
+		return nil, ErrResourceNotSupported
+	}
+	return getter.List(namespace, query)
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"testing"
+
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/constants"
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/models/resources/v1alpha1"
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/models/resources/v1alpha1/apps"
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/models/resources/v1alpha1/kruise"
+	kruisev1beta1 "github.com/openkruise/kruise-api/apps/v1beta1"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestTryResourceWithSharedName(t *testing.T) {
+	kruiseStatefulSet := kruise.NewStatefulSetObjectGetter(nil)
+	nativeStatefulSet := apps.NewStatefulSetObjectGetter(nil)
+	r := &ResourceGetter{
+		namespacedResourceGetters: map[schema.GroupVersionResource]v1alpha1.Interface{
+			kruisev1beta1.SchemeGroupVersion.WithResource(constants.StatefulSetType): kruiseStatefulSet,
+			appsv1.SchemeGroupVersion.WithResource(constants.StatefulSetType):        nativeStatefulSet,
+		},
+		clusterResourceGetters: map[schema.GroupVersionResource]v1alpha1.Interface{},
+	}
+
+	for i := 0; i < 10; i++ {
+		assert.Same(t, kruiseStatefulSet, r.TryResource(false, constants.StatefulSetType))
+	}
+	assert.Same(t, nativeStatefulSet, r.TryGroupVersionResource(false, appsv1.SchemeGroupVersion.WithResource(constants.StatefulSetType)))
+	assert.Same(t, kruiseStatefulSet, r.TryGroupVersionResource(true, kruisev1beta1.SchemeGroupVersion.WithResource(constants.StatefulSetType)))
+	assert.Nil(t, r.TryGroupVersionResource(false, appsv1.SchemeGroupVersion.WithResource(constants.DeploymentType)))
+}
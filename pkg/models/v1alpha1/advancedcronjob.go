@@ -0,0 +1,156 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"sort"
+
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/api"
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/constants"
+	"github.com/openkruise/kruise-api/apps/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	jobRunRunning   = "running"
+	jobRunCompleted = "completed"
+	jobRunFailed    = "failed"
+)
+
+// JobRun is a single run spawned by an AdvancedCronJob, either a Job or a BroadcastJob.
+type JobRun struct {
+	Kind           string   `json:"kind" description:"kind of the spawned job, Job or BroadcastJob"`
+	Name           string   `json:"name" description:"name of the spawned job"`
+	Namespace      string   `json:"namespace" description:"namespace of the spawned job"`
+	Status         string   `json:"status" description:"running, completed or failed"`
+	Completed      bool     `json:"completed" description:"whether the job has finished, successfully or not"`
+	Active         int32    `json:"active" description:"number of actively running pods"`
+	Succeeded      int32    `json:"succeeded" description:"number of succeeded pods"`
+	Failed         int32    `json:"failed" description:"number of failed pods"`
+	CreationTime   v1.Time  `json:"creationTime" description:"creation time of the job"`
+	StartTime      *v1.Time `json:"startTime,omitempty" description:"time the job started"`
+	CompletionTime *v1.Time `json:"completionTime,omitempty" description:"time the job completed"`
+}
+
+// ListAdvancedCronJobRuns lists the Jobs and BroadcastJobs owned by the AdvancedCronJob, newest first.
+func (c *operator) ListAdvancedCronJobRuns(namespace, name string) (*api.ListResult, error) {
+	obj, err := c.resourceGetter.Get(constants.AdvancedCronJobType, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	advancedCronJob := obj.(*v1alpha1.AdvancedCronJob)
+
+	var runs []JobRun
+
+	jobs, err := c.jobLister.Jobs(namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, job := range jobs {
+		if !isOwnedBy(job.ObjectMeta, advancedCronJob.UID) {
+			continue
+		}
+		runs = append(runs, jobRunFromJob(job))
+	}
+
+	broadcastJobs, err := c.broadcastJobLister.BroadcastJobs(namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, broadcastJob := range broadcastJobs {
+		if !isOwnedBy(broadcastJob.ObjectMeta, advancedCronJob.UID) {
+			continue
+		}
+		runs = append(runs, jobRunFromBroadcastJob(broadcastJob))
+	}
+
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[j].CreationTime.Before(&runs[i].CreationTime)
+	})
+
+	items := make([]interface{}, 0, len(runs))
+	for _, run := range runs {
+		items = append(items, run)
+	}
+	return api.NewListResult(items, len(items)), nil
+}
+
+func isOwnedBy(meta v1.ObjectMeta, uid types.UID) bool {
+	for _, ownerReference := range meta.OwnerReferences {
+		if ownerReference.UID == uid {
+			return true
+		}
+	}
+	return false
+}
+
+func jobRunFromJob(job *batchv1.Job) JobRun {
+	run := JobRun{
+		Kind:           "Job",
+		Name:           job.Name,
+		Namespace:      job.Namespace,
+		Status:         jobRunRunning,
+		Active:         job.Status.Active,
+		Succeeded:      job.Status.Succeeded,
+		Failed:         job.Status.Failed,
+		CreationTime:   job.CreationTimestamp,
+		StartTime:      job.Status.StartTime,
+		CompletionTime: job.Status.CompletionTime,
+	}
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			run.Status = jobRunCompleted
+			run.Completed = true
+		case batchv1.JobFailed:
+			run.Status = jobRunFailed
+			run.Completed = true
+		}
+	}
+	return run
+}
+
+func jobRunFromBroadcastJob(broadcastJob *v1alpha1.BroadcastJob) JobRun {
+	run := JobRun{
+		Kind:           constants.BroadcastJobTag,
+		Name:           broadcastJob.Name,
+		Namespace:      broadcastJob.Namespace,
+		Status:         jobRunRunning,
+		Active:         broadcastJob.Status.Active,
+		Succeeded:      broadcastJob.Status.Succeeded,
+		Failed:         broadcastJob.Status.Failed,
+		CreationTime:   broadcastJob.CreationTimestamp,
+		StartTime:      broadcastJob.Status.StartTime,
+		CompletionTime: broadcastJob.Status.CompletionTime,
+	}
+	switch broadcastJob.Status.Phase {
+	case v1alpha1.PhaseCompleted:
+		run.Status = jobRunCompleted
+		run.Completed = true
+	case v1alpha1.PhaseFailed:
+		run.Status = jobRunFailed
+		run.Completed = true
+	}
+	return run
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/openkruise/kruise-api/apps/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestIsOwnedBy(t *testing.T) {
+	meta := v1.ObjectMeta{OwnerReferences: []v1.OwnerReference{{UID: "cron-a"}, {UID: "cron-b"}}}
+	tests := []struct {
+		name string
+		meta v1.ObjectMeta
+		uid  types.UID
+		want bool
+	}{
+		{name: "first owner", meta: meta, uid: "cron-a", want: true},
+		{name: "second owner", meta: meta, uid: "cron-b", want: true},
+		{name: "other owner", meta: meta, uid: "cron-c", want: false},
+		{name: "no owners", meta: v1.ObjectMeta{}, uid: "cron-a", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isOwnedBy(tt.meta, tt.uid))
+		})
+	}
+}
+
+func TestJobRunFromJob(t *testing.T) {
+	started := v1.Now()
+	condition := func(conditionType batchv1.JobConditionType, status corev1.ConditionStatus) batchv1.JobCondition {
+		return batchv1.JobCondition{Type: conditionType, Status: status}
+	}
+	tests := []struct {
+		name          string
+		status        batchv1.JobStatus
+		wantStatus    string
+		wantCompleted bool
+	}{
+		{
+			name:       "active",
+			status:     batchv1.JobStatus{Active: 2, StartTime: &started},
+			wantStatus: jobRunRunning,
+		},
+		{
+			name: "suspended condition is still running",
+			status: batchv1.JobStatus{
+				Conditions: []batchv1.JobCondition{condition(batchv1.JobSuspended, corev1.ConditionTrue)},
+			},
+			wantStatus: jobRunRunning,
+		},
+		{
+			name: "completed",
+			status: batchv1.JobStatus{
+				Succeeded:      3,
+				StartTime:      &started,
+				CompletionTime: &started,
+				Conditions:     []batchv1.JobCondition{condition(batchv1.JobComplete, corev1.ConditionTrue)},
+			},
+			wantStatus:    jobRunCompleted,
+			wantCompleted: true,
+		},
+		{
+			name: "failed",
+			status: batchv1.JobStatus{
+				Failed:     1,
+				StartTime:  &started,
+				Conditions: []batchv1.JobCondition{condition(batchv1.JobFailed, corev1.ConditionTrue)},
+			},
+			wantStatus:    jobRunFailed,
+			wantCompleted: true,
+		},
+		{
+			name: "false conditions are ignored",
+			status: batchv1.JobStatus{
+				Active:     1,
+				Conditions: []batchv1.JobCondition{condition(batchv1.JobFailed, corev1.ConditionFalse)},
+			},
+			wantStatus: jobRunRunning,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := &batchv1.Job{
+				ObjectMeta: v1.ObjectMeta{Name: "cron-1", Namespace: "default", CreationTimestamp: started},
+				Status:     tt.status,
+			}
+			run := jobRunFromJob(job)
+			assert.Equal(t, "Job", run.Kind)
+			assert.Equal(t, "cron-1", run.Name)
+			assert.Equal(t, "default", run.Namespace)
+			assert.Equal(t, tt.wantStatus, run.Status)
+			assert.Equal(t, tt.wantCompleted, run.Completed)
+			assert.Equal(t, tt.status.Active, run.Active)
+			assert.Equal(t, tt.status.Succeeded, run.Succeeded)
+			assert.Equal(t, tt.status.Failed, run.Failed)
+			assert.Equal(t, tt.status.StartTime, run.StartTime)
+			assert.Equal(t, tt.status.CompletionTime, run.CompletionTime)
+		})
+	}
+}
+
+func TestJobRunFromBroadcastJob(t *testing.T) {
+	started := v1.Now()
+	tests := []struct {
+		name          string
+		status        v1alpha1.BroadcastJobStatus
+		wantStatus    string
+		wantCompleted bool
+	}{
+		{
+			name:       "active",
+			status:     v1alpha1.BroadcastJobStatus{Active: 2, Phase: v1alpha1.PhaseRunning, StartTime: &started},
+			wantStatus: jobRunRunning,
+		},
+		{
+			name:       "paused is still running",
+			status:     v1alpha1.BroadcastJobStatus{Phase: v1alpha1.PhasePaused},
+			wantStatus: jobRunRunning,
+		},
+		{
+			name:          "completed",
+			status:        v1alpha1.BroadcastJobStatus{Succeeded: 3, Phase: v1alpha1.PhaseCompleted, StartTime: &started, CompletionTime: &started},
+			wantStatus:    jobRunCompleted,
+			wantCompleted: true,
+		},
+		{
+			name:          "failed",
+			status:        v1alpha1.BroadcastJobStatus{Failed: 1, Phase: v1alpha1.PhaseFailed, StartTime: &started},
+			wantStatus:    jobRunFailed,
+			wantCompleted: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broadcastJob := &v1alpha1.BroadcastJob{
+				ObjectMeta: v1.ObjectMeta{Name: "cron-1", Namespace: "default", CreationTimestamp: started},
+				Status:     tt.status,
+			}
+			run := jobRunFromBroadcastJob(broadcastJob)
+			assert.Equal(t, "BroadcastJob", run.Kind)
+			assert.Equal(t, "cron-1", run.Name)
+			assert.Equal(t, tt.wantStatus, run.Status)
+			assert.Equal(t, tt.wantCompleted, run.Completed)
+			assert.Equal(t, tt.status.Active, run.Active)
+			assert.Equal(t, tt.status.Succeeded, run.Succeeded)
+			assert.Equal(t, tt.status.Failed, run.Failed)
+			assert.Equal(t, tt.status.CompletionTime, run.CompletionTime)
+		})
+	}
+}
@@ -3,6 +3,8 @@ package v1alpha1
 import (
 	"context"
 	"fmt"
+	"io"
+
 	"github.com/Gentleelephant/EnhancementWorkload/pkg/api"
 	"github.com/Gentleelephant/EnhancementWorkload/pkg/apiserver/query"
 	"github.com/Gentleelephant/EnhancementWorkload/pkg/constants"
@@ -10,36 +12,92 @@ import (
 	"github.com/Gentleelephant/EnhancementWorkload/pkg/models/resources/v1alpha1/resource"
 	"github.com/duke-git/lancet/v2/slice"
 	"github.com/openkruise/kruise-api/apps/v1alpha1"
+	"github.com/openkruise/kruise-api/apps/v1beta1"
 	kruiseclientset "github.com/openkruise/kruise-api/client/clientset/versioned"
+	kruiselisters "github.com/openkruise/kruise-api/client/listers/apps/v1alpha1"
+	kruisepolicyv1alpha1 "github.com/openkruise/kruise-api/policy/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes"
+	batchlisters "k8s.io/client-go/listers/batch/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
 )
 
 type Operator interface {
 	List(namespace, resource string, query *query.Query) (*api.ListResult, error)
 	Get(namespace, resource, name string) (runtime.Object, error)
+	ListNative(namespace, resource string, query *query.Query) (*api.ListResult, error)
+	GetNative(namespace, resource, name string) (runtime.Object, error)
 	Create(namespace, resource string, obj runtime.Object) (runtime.Object, error)
 	Update(namespace, resource, name string, obj runtime.Object) (runtime.Object, error)
 	Delete(namespace, resource, name string) error
 	ListPods(namespace, resource, name string) (*api.ListResult, error)
+	ListAdvancedCronJobRuns(namespace, name string) (*api.ListResult, error)
+	ListUnitedDeploymentSubsets(namespace, name string) (*api.ListResult, error)
+	GetCloneSetSpread(namespace, name string) (*CloneSetSpread, error)
+	GetCloneSetPodUnavailableBudget(namespace, name string) (*WorkloadPodUnavailableBudget, error)
+	RestartCloneSetPod(namespace, name, podName string, restart *RestartContainers) (*v1alpha1.ContainerRecreateRequest, error)
+	ListCloneSetPodRestarts(namespace, name, podName string) (*api.ListResult, error)
+	PullCloneSetImages(namespace, name string, pull *PullImages) (*api.ListResult, error)
+	ListResourceDistributionTargets(name string) (*api.ListResult, error)
+	ListPodProbeResults(namespace, name string) (*api.ListResult, error)
+	ListEphemeralJobPods(namespace, name string) (*api.ListResult, error)
+	MigrateDeployment(namespace, name string, migrate *MigrateDeployment) (*DeploymentMigration, error)
+	GetDeploymentMigration(namespace, name string) (*DeploymentMigrationStatus, error)
+	GetCloneSetScale(namespace, name string) (*autoscalingv1.Scale, error)
+	ScaleCloneSet(namespace, name string, scale *CloneSetScale) (*autoscalingv1.Scale, error)
+	DeleteCloneSetPods(namespace, name string, pods *DeleteCloneSetPods) (*v1alpha1.CloneSet, error)
+	PauseCloneSetRollout(namespace, name string) (*CloneSetRollout, error)
+	ResumeCloneSetRollout(namespace, name string) (*CloneSetRollout, error)
+	StepCloneSetRollout(namespace, name string, step *RolloutStep) (*CloneSetRollout, error)
+	SetCloneSetRolloutPlan(namespace, name string, plan *RolloutPlan) (*CloneSetRolloutPlan, error)
+	GetCloneSetRolloutPlan(namespace, name string) (*CloneSetRolloutPlan, error)
+	DeleteCloneSetRolloutPlan(namespace, name string) error
+	SetCloneSetRollbackPolicy(namespace, name string, policy *RollbackPolicy) (*CloneSetAutoRollback, error)
+	GetCloneSetRollbackPolicy(namespace, name string) (*CloneSetAutoRollback, error)
+	DeleteCloneSetRollbackPolicy(namespace, name string) error
+	ListCloneSetRevisionHistory(namespace, name string) ([]CloneSetRevision, error)
+	RollbackCloneSetToRevision(namespace, name string, rollback *CloneSetRollback) (*v1alpha1.CloneSet, error)
+	DiffCloneSetRevisions(namespace, name string, from, to int64) (*ResourceDiff, error)
+	DiffSidecarSetRevisions(name string, from, to int64) (*ResourceDiff, error)
+	DiffResource(namespace, resource, name string, proposed runtime.Object) (*ResourceDiff, error)
+	SetCloneSetImages(namespace, name string, images *SetCloneSetImages) (*CloneSetImageUpdate, error)
+	ListWorkloadPods(namespace, resource, name string) (*api.ListResult, error)
+	CompletePodLifecycleHook(namespace, resource, name, podName string) (*LifecyclePod, error)
+	GetCloneSetStatus(namespace, name string) (*CloneSetRolloutStatus, error)
+	ListWorkloadEvents(namespace, resource, name string) (*api.ListResult, error)
+	StreamPodLogs(ctx context.Context, namespace, podName string, options *corev1.PodLogOptions) (io.ReadCloser, error)
+	StreamCloneSetLogs(ctx context.Context, namespace, name string, options *corev1.PodLogOptions) (io.ReadCloser, error)
 
 	VerifyResouces(namespace string, obj runtime.Object) error
 	IsKnownResource(resource string) bool
+	IsKnownGroupVersionResource(gvr schema.GroupVersionResource) bool
 	GetObject(resource string) runtime.Object
 }
 
+// podWorkloadTypes are the resources ListPods resolves pods for.
+var podWorkloadTypes = []string{constants.SidecarSetType, constants.CloneSetType, constants.StatefulSetType, constants.DaemonSetType, constants.PodProbeMarkerType, constants.EphemeralJobType}
+
 type operator struct {
-	kubernetesclientset kubernetes.Interface
-	kruiseclientset     kruiseclientset.Interface
-	resourceGetter      *resource.ResourceGetter
+	kubernetesclientset            kubernetes.Interface
+	kruiseclientset                kruiseclientset.Interface
+	resourceGetter                 *resource.ResourceGetter
+	eventLister                    corelisters.EventLister
+	jobLister                      batchlisters.JobLister
+	broadcastJobLister             kruiselisters.BroadcastJobLister
+	workloadSpreadLister           kruiselisters.WorkloadSpreadLister
+	containerRecreateRequestLister kruiselisters.ContainerRecreateRequestLister
 }
 
 func (c *operator) ListPods(namespace, resource, name string) (*api.ListResult, error) {
 
-	if !slice.Contain([]string{constants.SidecarSetType, constants.CloneSetType}, resource) {
+	if !slice.Contain(podWorkloadTypes, resource) {
 		return nil, errors.NewBadRequest("resource type is not supported")
 	}
 
@@ -65,11 +123,58 @@ func (c *operator) ListPods(namespace, resource, name string) (*api.ListResult,
 			return nil, err
 		}
 		return c.listPods(namespace, matchLabels)
+	case constants.StatefulSetType:
+		statefulSet := workload.(*v1beta1.StatefulSet)
+		selector := statefulSet.Spec.Selector
+		matchLabels, err := v1.LabelSelectorAsMap(selector)
+		if err != nil {
+			return nil, err
+		}
+		return c.listPods(namespace, matchLabels)
+	case constants.DaemonSetType:
+		daemonSet := workload.(*v1alpha1.DaemonSet)
+		selector := daemonSet.Spec.Selector
+		matchLabels, err := v1.LabelSelectorAsMap(selector)
+		if err != nil {
+			return nil, err
+		}
+		return c.listPods(namespace, matchLabels)
+	case constants.PodProbeMarkerType:
+		podProbeMarker := workload.(*v1alpha1.PodProbeMarker)
+		selector := podProbeMarker.Spec.Selector
+		matchLabels, err := v1.LabelSelectorAsMap(selector)
+		if err != nil {
+			return nil, err
+		}
+		return c.listPods(namespace, matchLabels)
+	case constants.EphemeralJobType:
+		ephemeralJob := workload.(*v1alpha1.EphemeralJob)
+		selector := ephemeralJob.Spec.Selector
+		matchLabels, err := v1.LabelSelectorAsMap(selector)
+		if err != nil {
+			return nil, err
+		}
+		return c.listPods(namespace, matchLabels)
 	default:
 		return nil, errors.NewInternalError(nil)
 	}
 }
 
+// getWorkloadPod returns the named pod if it is one of the pods resolved for the workload by ListPods.
+func (c *operator) getWorkloadPod(namespace, resource, name, podName string) (*corev1.Pod, error) {
+	pods, err := c.ListPods(namespace, resource, name)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range pods.Items {
+		pod := item.(*corev1.Pod)
+		if pod.Name == podName {
+			return pod, nil
+		}
+	}
+	return nil, errors.NewNotFound(corev1.Resource(constants.PodType), podName)
+}
+
 func (c *operator) listPods(namespace string, matchLabels map[string]string) (*api.ListResult, error) {
 	podList, err := c.kubernetesclientset.CoreV1().Pods(namespace).List(context.Background(), v1.ListOptions{LabelSelector: labels.Set(matchLabels).String()})
 	if err != nil {
@@ -84,8 +189,8 @@ func (c *operator) listPods(namespace string, matchLabels map[string]string) (*a
 	}
 
 	var objs []interface{}
-	for _, item := range podList.Items {
-		objs = append(objs, &item)
+	for i := range podList.Items {
+		objs = append(objs, &podList.Items[i])
 	}
 	return &api.ListResult{
 		Items:      objs,
@@ -105,6 +210,16 @@ func (c *operator) Get(namespace, resource, name string) (runtime.Object, error)
 	return obj, nil
 }
 
+// ListNative lists native apps/v1 workloads, which share resource names with the kruise ones.
+func (c *operator) ListNative(namespace, resource string, query *query.Query) (*api.ListResult, error) {
+	return c.resourceGetter.ListWithGroupVersion(appsv1.SchemeGroupVersion.WithResource(resource), namespace, query)
+}
+
+// GetNative gets a native apps/v1 workload.
+func (c *operator) GetNative(namespace, resource, name string) (runtime.Object, error) {
+	return c.resourceGetter.GetWithGroupVersion(appsv1.SchemeGroupVersion.WithResource(resource), namespace, name)
+}
+
 func (c *operator) Create(namespace, resource string, obj runtime.Object) (runtime.Object, error) {
 	switch resource {
 	case constants.CloneSetType:
@@ -119,31 +234,225 @@ func (c *operator) Create(namespace, resource string, obj runtime.Object) (runti
 			return nil, fmt.Errorf("object is not a SidecarSet")
 		}
 		return c.kruiseclientset.AppsV1alpha1().SidecarSets().Create(context.Background(), sidecarset, v1.CreateOptions{})
+	case constants.StatefulSetType:
+		statefulSet, ok := obj.(*v1beta1.StatefulSet)
+		if !ok {
+			return nil, fmt.Errorf("object is not a StatefulSet")
+		}
+		return c.kruiseclientset.AppsV1beta1().StatefulSets(namespace).Create(context.Background(), statefulSet, v1.CreateOptions{})
+	case constants.DaemonSetType:
+		daemonSet, ok := obj.(*v1alpha1.DaemonSet)
+		if !ok {
+			return nil, fmt.Errorf("object is not a DaemonSet")
+		}
+		return c.kruiseclientset.AppsV1alpha1().DaemonSets(namespace).Create(context.Background(), daemonSet, v1.CreateOptions{})
+	case constants.BroadcastJobType:
+		broadcastJob, ok := obj.(*v1alpha1.BroadcastJob)
+		if !ok {
+			return nil, fmt.Errorf("object is not a BroadcastJob")
+		}
+		return c.kruiseclientset.AppsV1alpha1().BroadcastJobs(namespace).Create(context.Background(), broadcastJob, v1.CreateOptions{})
+	case constants.AdvancedCronJobType:
+		advancedCronJob, ok := obj.(*v1alpha1.AdvancedCronJob)
+		if !ok {
+			return nil, fmt.Errorf("object is not an AdvancedCronJob")
+		}
+		return c.kruiseclientset.AppsV1alpha1().AdvancedCronJobs(namespace).Create(context.Background(), advancedCronJob, v1.CreateOptions{})
+	case constants.UnitedDeploymentType:
+		unitedDeployment, ok := obj.(*v1alpha1.UnitedDeployment)
+		if !ok {
+			return nil, fmt.Errorf("object is not a UnitedDeployment")
+		}
+		return c.kruiseclientset.AppsV1alpha1().UnitedDeployments(namespace).Create(context.Background(), unitedDeployment, v1.CreateOptions{})
+	case constants.WorkloadSpreadType:
+		workloadSpread, ok := obj.(*v1alpha1.WorkloadSpread)
+		if !ok {
+			return nil, fmt.Errorf("object is not a WorkloadSpread")
+		}
+		return c.kruiseclientset.AppsV1alpha1().WorkloadSpreads(namespace).Create(context.Background(), workloadSpread, v1.CreateOptions{})
+	case constants.ContainerRecreateRequestType:
+		containerRecreateRequest, ok := obj.(*v1alpha1.ContainerRecreateRequest)
+		if !ok {
+			return nil, fmt.Errorf("object is not a ContainerRecreateRequest")
+		}
+		return c.kruiseclientset.AppsV1alpha1().ContainerRecreateRequests(namespace).Create(context.Background(), containerRecreateRequest, v1.CreateOptions{})
+	case constants.ImagePullJobType:
+		imagePullJob, ok := obj.(*v1alpha1.ImagePullJob)
+		if !ok {
+			return nil, fmt.Errorf("object is not an ImagePullJob")
+		}
+		return c.kruiseclientset.AppsV1alpha1().ImagePullJobs(namespace).Create(context.Background(), imagePullJob, v1.CreateOptions{})
+	case constants.NodeImageType:
+		return nil, errors.NewMethodNotSupported(v1alpha1.Resource(resource), "create")
+	case constants.ResourceDistributionType:
+		resourceDistribution, ok := obj.(*v1alpha1.ResourceDistribution)
+		if !ok {
+			return nil, fmt.Errorf("object is not a ResourceDistribution")
+		}
+		return c.kruiseclientset.AppsV1alpha1().ResourceDistributions().Create(context.Background(), resourceDistribution, v1.CreateOptions{})
+	case constants.PodProbeMarkerType:
+		podProbeMarker, ok := obj.(*v1alpha1.PodProbeMarker)
+		if !ok {
+			return nil, fmt.Errorf("object is not a PodProbeMarker")
+		}
+		return c.kruiseclientset.AppsV1alpha1().PodProbeMarkers(namespace).Create(context.Background(), podProbeMarker, v1.CreateOptions{})
+	case constants.NodePodProbeType:
+		return nil, errors.NewMethodNotSupported(v1alpha1.Resource(resource), "create")
+	case constants.PersistentPodStateType:
+		persistentPodState, ok := obj.(*v1alpha1.PersistentPodState)
+		if !ok {
+			return nil, fmt.Errorf("object is not a PersistentPodState")
+		}
+		return c.kruiseclientset.AppsV1alpha1().PersistentPodStates(namespace).Create(context.Background(), persistentPodState, v1.CreateOptions{})
+	case constants.EphemeralJobType:
+		ephemeralJob, ok := obj.(*v1alpha1.EphemeralJob)
+		if !ok {
+			return nil, fmt.Errorf("object is not an EphemeralJob")
+		}
+		return c.kruiseclientset.AppsV1alpha1().EphemeralJobs(namespace).Create(context.Background(), ephemeralJob, v1.CreateOptions{})
+	case constants.PodUnavailableBudgetType:
+		podUnavailableBudget, ok := obj.(*kruisepolicyv1alpha1.PodUnavailableBudget)
+		if !ok {
+			return nil, fmt.Errorf("object is not a PodUnavailableBudget")
+		}
+		return c.kruiseclientset.PolicyV1alpha1().PodUnavailableBudgets(namespace).Create(context.Background(), podUnavailableBudget, v1.CreateOptions{})
 	default:
 		return nil, errors.NewInternalError(nil)
 	}
 }
 
 func (c *operator) Update(namespace, resource, name string, obj runtime.Object) (runtime.Object, error) {
+	return c.update(namespace, resource, name, obj, v1.UpdateOptions{})
+}
+
+func (c *operator) update(namespace, resource, name string, obj runtime.Object, options v1.UpdateOptions) (runtime.Object, error) {
 	old, err := c.resourceGetter.Get(resource, namespace, name)
 	if err != nil {
 		return nil, err
 	}
 
 	switch resource {
-	case constants.CloneSetTag:
+	case constants.CloneSetType:
 		oldScaledObject := old.(*v1alpha1.CloneSet)
 		newCloneset, ok := obj.(*v1alpha1.CloneSet)
 		if !ok {
 			return nil, fmt.Errorf("object is not a CloneSet")
 		}
 		newCloneset.SetResourceVersion(oldScaledObject.ResourceVersion)
-		return c.kruiseclientset.AppsV1alpha1().CloneSets(namespace).Update(context.Background(), newCloneset, v1.UpdateOptions{})
+		return c.kruiseclientset.AppsV1alpha1().CloneSets(namespace).Update(context.Background(), newCloneset, options)
 	case constants.SidecarSetType:
 		oldScaledJob := old.(*v1alpha1.SidecarSet)
 		NewScaledJob := obj.(*v1alpha1.SidecarSet)
 		NewScaledJob.SetResourceVersion(oldScaledJob.ResourceVersion)
-		return c.kruiseclientset.AppsV1alpha1().SidecarSets().Update(context.Background(), NewScaledJob, v1.UpdateOptions{})
+		return c.kruiseclientset.AppsV1alpha1().SidecarSets().Update(context.Background(), NewScaledJob, options)
+	case constants.StatefulSetType:
+		oldStatefulSet := old.(*v1beta1.StatefulSet)
+		newStatefulSet, ok := obj.(*v1beta1.StatefulSet)
+		if !ok {
+			return nil, fmt.Errorf("object is not a StatefulSet")
+		}
+		newStatefulSet.SetResourceVersion(oldStatefulSet.ResourceVersion)
+		return c.kruiseclientset.AppsV1beta1().StatefulSets(namespace).Update(context.Background(), newStatefulSet, options)
+	case constants.DaemonSetType:
+		oldDaemonSet := old.(*v1alpha1.DaemonSet)
+		newDaemonSet, ok := obj.(*v1alpha1.DaemonSet)
+		if !ok {
+			return nil, fmt.Errorf("object is not a DaemonSet")
+		}
+		newDaemonSet.SetResourceVersion(oldDaemonSet.ResourceVersion)
+		return c.kruiseclientset.AppsV1alpha1().DaemonSets(namespace).Update(context.Background(), newDaemonSet, options)
+	case constants.BroadcastJobType:
+		oldBroadcastJob := old.(*v1alpha1.BroadcastJob)
+		newBroadcastJob, ok := obj.(*v1alpha1.BroadcastJob)
+		if !ok {
+			return nil, fmt.Errorf("object is not a BroadcastJob")
+		}
+		newBroadcastJob.SetResourceVersion(oldBroadcastJob.ResourceVersion)
+		return c.kruiseclientset.AppsV1alpha1().BroadcastJobs(namespace).Update(context.Background(), newBroadcastJob, options)
+	case constants.AdvancedCronJobType:
+		oldAdvancedCronJob := old.(*v1alpha1.AdvancedCronJob)
+		newAdvancedCronJob, ok := obj.(*v1alpha1.AdvancedCronJob)
+		if !ok {
+			return nil, fmt.Errorf("object is not an AdvancedCronJob")
+		}
+		newAdvancedCronJob.SetResourceVersion(oldAdvancedCronJob.ResourceVersion)
+		return c.kruiseclientset.AppsV1alpha1().AdvancedCronJobs(namespace).Update(context.Background(), newAdvancedCronJob, options)
+	case constants.UnitedDeploymentType:
+		oldUnitedDeployment := old.(*v1alpha1.UnitedDeployment)
+		newUnitedDeployment, ok := obj.(*v1alpha1.UnitedDeployment)
+		if !ok {
+			return nil, fmt.Errorf("object is not a UnitedDeployment")
+		}
+		newUnitedDeployment.SetResourceVersion(oldUnitedDeployment.ResourceVersion)
+		return c.kruiseclientset.AppsV1alpha1().UnitedDeployments(namespace).Update(context.Background(), newUnitedDeployment, options)
+	case constants.WorkloadSpreadType:
+		oldWorkloadSpread := old.(*v1alpha1.WorkloadSpread)
+		newWorkloadSpread, ok := obj.(*v1alpha1.WorkloadSpread)
+		if !ok {
+			return nil, fmt.Errorf("object is not a WorkloadSpread")
+		}
+		newWorkloadSpread.SetResourceVersion(oldWorkloadSpread.ResourceVersion)
+		return c.kruiseclientset.AppsV1alpha1().WorkloadSpreads(namespace).Update(context.Background(), newWorkloadSpread, options)
+	case constants.ContainerRecreateRequestType:
+		oldContainerRecreateRequest := old.(*v1alpha1.ContainerRecreateRequest)
+		newContainerRecreateRequest, ok := obj.(*v1alpha1.ContainerRecreateRequest)
+		if !ok {
+			return nil, fmt.Errorf("object is not a ContainerRecreateRequest")
+		}
+		newContainerRecreateRequest.SetResourceVersion(oldContainerRecreateRequest.ResourceVersion)
+		return c.kruiseclientset.AppsV1alpha1().ContainerRecreateRequests(namespace).Update(context.Background(), newContainerRecreateRequest, options)
+	case constants.ImagePullJobType:
+		oldImagePullJob := old.(*v1alpha1.ImagePullJob)
+		newImagePullJob, ok := obj.(*v1alpha1.ImagePullJob)
+		if !ok {
+			return nil, fmt.Errorf("object is not an ImagePullJob")
+		}
+		newImagePullJob.SetResourceVersion(oldImagePullJob.ResourceVersion)
+		return c.kruiseclientset.AppsV1alpha1().ImagePullJobs(namespace).Update(context.Background(), newImagePullJob, options)
+	case constants.NodeImageType:
+		return nil, errors.NewMethodNotSupported(v1alpha1.Resource(resource), "update")
+	case constants.ResourceDistributionType:
+		oldResourceDistribution := old.(*v1alpha1.ResourceDistribution)
+		newResourceDistribution, ok := obj.(*v1alpha1.ResourceDistribution)
+		if !ok {
+			return nil, fmt.Errorf("object is not a ResourceDistribution")
+		}
+		newResourceDistribution.SetResourceVersion(oldResourceDistribution.ResourceVersion)
+		return c.kruiseclientset.AppsV1alpha1().ResourceDistributions().Update(context.Background(), newResourceDistribution, options)
+	case constants.PodProbeMarkerType:
+		oldPodProbeMarker := old.(*v1alpha1.PodProbeMarker)
+		newPodProbeMarker, ok := obj.(*v1alpha1.PodProbeMarker)
+		if !ok {
+			return nil, fmt.Errorf("object is not a PodProbeMarker")
+		}
+		newPodProbeMarker.SetResourceVersion(oldPodProbeMarker.ResourceVersion)
+		return c.kruiseclientset.AppsV1alpha1().PodProbeMarkers(namespace).Update(context.Background(), newPodProbeMarker, options)
+	case constants.NodePodProbeType:
+		return nil, errors.NewMethodNotSupported(v1alpha1.Resource(resource), "update")
+	case constants.PersistentPodStateType:
+		oldPersistentPodState := old.(*v1alpha1.PersistentPodState)
+		newPersistentPodState, ok := obj.(*v1alpha1.PersistentPodState)
+		if !ok {
+			return nil, fmt.Errorf("object is not a PersistentPodState")
+		}
+		newPersistentPodState.SetResourceVersion(oldPersistentPodState.ResourceVersion)
+		return c.kruiseclientset.AppsV1alpha1().PersistentPodStates(namespace).Update(context.Background(), newPersistentPodState, options)
+	case constants.EphemeralJobType:
+		oldEphemeralJob := old.(*v1alpha1.EphemeralJob)
+		newEphemeralJob, ok := obj.(*v1alpha1.EphemeralJob)
+		if !ok {
+			return nil, fmt.Errorf("object is not an EphemeralJob")
+		}
+		newEphemeralJob.SetResourceVersion(oldEphemeralJob.ResourceVersion)
+		return c.kruiseclientset.AppsV1alpha1().EphemeralJobs(namespace).Update(context.Background(), newEphemeralJob, options)
+	case constants.PodUnavailableBudgetType:
+		oldPodUnavailableBudget := old.(*kruisepolicyv1alpha1.PodUnavailableBudget)
+		newPodUnavailableBudget, ok := obj.(*kruisepolicyv1alpha1.PodUnavailableBudget)
+		if !ok {
+			return nil, fmt.Errorf("object is not a PodUnavailableBudget")
+		}
+		newPodUnavailableBudget.SetResourceVersion(oldPodUnavailableBudget.ResourceVersion)
+		return c.kruiseclientset.PolicyV1alpha1().PodUnavailableBudgets(namespace).Update(context.Background(), newPodUnavailableBudget, options)
 	default:
 		return nil, errors.NewInternalError(nil)
 	}
@@ -156,6 +465,36 @@ func (c *operator) Delete(namespace, resource, name string) error {
 		return c.kruiseclientset.AppsV1alpha1().CloneSets(namespace).Delete(context.Background(), name, v1.DeleteOptions{})
 	case constants.SidecarSetType:
 		return c.kruiseclientset.AppsV1alpha1().SidecarSets().Delete(context.Background(), name, v1.DeleteOptions{})
+	case constants.StatefulSetType:
+		return c.kruiseclientset.AppsV1beta1().StatefulSets(namespace).Delete(context.Background(), name, v1.DeleteOptions{})
+	case constants.DaemonSetType:
+		return c.kruiseclientset.AppsV1alpha1().DaemonSets(namespace).Delete(context.Background(), name, v1.DeleteOptions{})
+	case constants.BroadcastJobType:
+		return c.kruiseclientset.AppsV1alpha1().BroadcastJobs(namespace).Delete(context.Background(), name, v1.DeleteOptions{})
+	case constants.AdvancedCronJobType:
+		return c.kruiseclientset.AppsV1alpha1().AdvancedCronJobs(namespace).Delete(context.Background(), name, v1.DeleteOptions{})
+	case constants.UnitedDeploymentType:
+		return c.kruiseclientset.AppsV1alpha1().UnitedDeployments(namespace).Delete(context.Background(), name, v1.DeleteOptions{})
+	case constants.WorkloadSpreadType:
+		return c.kruiseclientset.AppsV1alpha1().WorkloadSpreads(namespace).Delete(context.Background(), name, v1.DeleteOptions{})
+	case constants.ContainerRecreateRequestType:
+		return c.kruiseclientset.AppsV1alpha1().ContainerRecreateRequests(namespace).Delete(context.Background(), name, v1.DeleteOptions{})
+	case constants.ImagePullJobType:
+		return c.kruiseclientset.AppsV1alpha1().ImagePullJobs(namespace).Delete(context.Background(), name, v1.DeleteOptions{})
+	case constants.NodeImageType:
+		return errors.NewMethodNotSupported(v1alpha1.Resource(resource), "delete")
+	case constants.ResourceDistributionType:
+		return c.kruiseclientset.AppsV1alpha1().ResourceDistributions().Delete(context.Background(), name, v1.DeleteOptions{})
+	case constants.PodProbeMarkerType:
+		return c.kruiseclientset.AppsV1alpha1().PodProbeMarkers(namespace).Delete(context.Background(), name, v1.DeleteOptions{})
+	case constants.NodePodProbeType:
+		return errors.NewMethodNotSupported(v1alpha1.Resource(resource), "delete")
+	case constants.PersistentPodStateType:
+		return c.kruiseclientset.AppsV1alpha1().PersistentPodStates(namespace).Delete(context.Background(), name, v1.DeleteOptions{})
+	case constants.EphemeralJobType:
+		return c.kruiseclientset.AppsV1alpha1().EphemeralJobs(namespace).Delete(context.Background(), name, v1.DeleteOptions{})
+	case constants.PodUnavailableBudgetType:
+		return c.kruiseclientset.PolicyV1alpha1().PodUnavailableBudgets(namespace).Delete(context.Background(), name, v1.DeleteOptions{})
 	default:
 		return errors.NewInternalError(nil)
 	}
@@ -173,12 +512,47 @@ func (c *operator) IsKnownResource(resource string) bool {
 	return true
 }
 
+// IsKnownGroupVersionResource reports whether the resource is served in exactly this group version, namespaced or cluster scoped.
+func (c *operator) IsKnownGroupVersionResource(gvr schema.GroupVersionResource) bool {
+	return c.resourceGetter.TryGroupVersionResource(true, gvr) != nil
+}
+
 func (c *operator) GetObject(resource string) runtime.Object {
 	switch resource {
 	case constants.CloneSetType:
 		return &v1alpha1.CloneSet{}
 	case constants.SidecarSetType:
 		return &v1alpha1.SidecarSet{}
+	case constants.StatefulSetType:
+		return &v1beta1.StatefulSet{}
+	case constants.DaemonSetType:
+		return &v1alpha1.DaemonSet{}
+	case constants.BroadcastJobType:
+		return &v1alpha1.BroadcastJob{}
+	case constants.AdvancedCronJobType:
+		return &v1alpha1.AdvancedCronJob{}
+	case constants.UnitedDeploymentType:
+		return &v1alpha1.UnitedDeployment{}
+	case constants.WorkloadSpreadType:
+		return &v1alpha1.WorkloadSpread{}
+	case constants.ContainerRecreateRequestType:
+		return &v1alpha1.ContainerRecreateRequest{}
+	case constants.ImagePullJobType:
+		return &v1alpha1.ImagePullJob{}
+	case constants.NodeImageType:
+		return &v1alpha1.NodeImage{}
+	case constants.ResourceDistributionType:
+		return &v1alpha1.ResourceDistribution{}
+	case constants.PodProbeMarkerType:
+		return &v1alpha1.PodProbeMarker{}
+	case constants.NodePodProbeType:
+		return &v1alpha1.NodePodProbe{}
+	case constants.PersistentPodStateType:
+		return &v1alpha1.PersistentPodState{}
+	case constants.EphemeralJobType:
+		return &v1alpha1.EphemeralJob{}
+	case constants.PodUnavailableBudgetType:
+		return &kruisepolicyv1alpha1.PodUnavailableBudget{}
 	default:
 		return nil
 	}
@@ -186,8 +560,13 @@ func (c *operator) GetObject(resource string) runtime.Object {
 
 func NewOperator(informers informers.InformerFactory, clientset kruiseclientset.Interface, k8sclient kubernetes.Interface) Operator {
 	return &operator{
-		kruiseclientset:     clientset,
-		kubernetesclientset: k8sclient,
-		resourceGetter:      resource.NewResourceGetter(informers, nil),
+		kruiseclientset:                clientset,
+		kubernetesclientset:            k8sclient,
+		resourceGetter:                 resource.NewResourceGetter(informers, nil),
+		eventLister:                    informers.KubernetesSharedInformerFactory().Core().V1().Events().Lister(),
+		jobLister:                      informers.KubernetesSharedInformerFactory().Batch().V1().Jobs().Lister(),
+		broadcastJobLister:             informers.KruiseInformerFactory().Apps().V1alpha1().BroadcastJobs().Lister(),
+		workloadSpreadLister:           informers.KruiseInformerFactory().Apps().V1alpha1().WorkloadSpreads().Lister(),
+		containerRecreateRequestLister: informers.KruiseInformerFactory().Apps().V1alpha1().ContainerRecreateRequests().Lister(),
 	}
 }
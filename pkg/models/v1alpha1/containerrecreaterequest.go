@@ -0,0 +1,195 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/api"
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/constants"
+	"github.com/openkruise/kruise-api/apps/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// RestartContainers selects the containers of a pod to be recreated in place.
+type RestartContainers struct {
+	Containers              []string                                   `json:"containers,omitempty" description:"names of the containers to restart, all containers if empty"`
+	Strategy                *v1alpha1.ContainerRecreateRequestStrategy `json:"strategy,omitempty" description:"strategy of the container recreation"`
+	ActiveDeadlineSeconds   *int64                                     `json:"activeDeadlineSeconds,omitempty" description:"duration the request may stay active before it is marked failed"`
+	TTLSecondsAfterFinished *int32                                     `json:"ttlSecondsAfterFinished,omitempty" description:"duration the request is kept after it finished"`
+}
+
+// PodRestart is a ContainerRecreateRequest of a pod with the recreation phase of each of its containers.
+type PodRestart struct {
+	Name           string                                 `json:"name" description:"name of the containerrecreaterequest"`
+	Phase          v1alpha1.ContainerRecreateRequestPhase `json:"phase" description:"Pending, Recreating or Completed"`
+	Message        string                                 `json:"message,omitempty" description:"message of the containerrecreaterequest"`
+	CreationTime   v1.Time                                `json:"creationTime" description:"creation time of the containerrecreaterequest"`
+	CompletionTime *v1.Time                               `json:"completionTime,omitempty" description:"time the containerrecreaterequest completed"`
+	Containers     []ContainerRestart                     `json:"containers" description:"recreation phase of the containers to restart"`
+}
+
+// ContainerRestart is the recreation phase of one container of a ContainerRecreateRequest.
+type ContainerRestart struct {
+	Name     string                                 `json:"name" description:"name of the container"`
+	Phase    v1alpha1.ContainerRecreateRequestPhase `json:"phase" description:"Pending, Recreating, Succeeded or Failed"`
+	IsKilled bool                                   `json:"isKilled" description:"whether kruise-daemon has killed the container"`
+	Message  string                                 `json:"message,omitempty" description:"message of the container recreation"`
+}
+
+// RestartCloneSetPod creates a ContainerRecreateRequest for the selected containers of a pod owned by the CloneSet.
+func (c *operator) RestartCloneSetPod(namespace, name, podName string, restart *RestartContainers) (*v1alpha1.ContainerRecreateRequest, error) {
+	obj, err := c.resourceGetter.Get(constants.CloneSetType, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	cloneSet := obj.(*v1alpha1.CloneSet)
+
+	pod, err := c.getWorkloadPod(namespace, constants.CloneSetType, name, podName)
+	if err != nil {
+		return nil, err
+	}
+
+	containerRecreateRequest, err := newContainerRecreateRequest(cloneSet, pod, restart)
+	if err != nil {
+		return nil, err
+	}
+	return c.kruiseclientset.AppsV1alpha1().ContainerRecreateRequests(namespace).Create(context.Background(), containerRecreateRequest, v1.CreateOptions{})
+}
+
+// ListCloneSetPodRestarts lists the ContainerRecreateRequests of a pod owned by the CloneSet, newest first.
+func (c *operator) ListCloneSetPodRestarts(namespace, name, podName string) (*api.ListResult, error) {
+	pod, err := c.getWorkloadPod(namespace, constants.CloneSetType, name, podName)
+	if err != nil {
+		return nil, err
+	}
+
+	containerRecreateRequests, err := c.containerRecreateRequestLister.ContainerRecreateRequests(namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	restarts := podRestarts(pod, containerRecreateRequests)
+
+	items := make([]interface{}, 0, len(restarts))
+	for _, restart := range restarts {
+		items = append(items, restart)
+	}
+	return api.NewListResult(items, len(items)), nil
+}
+
+// newContainerRecreateRequest builds the request restarting the selected containers of the pod, all of
+// its containers if none are selected.
+func newContainerRecreateRequest(cloneSet *v1alpha1.CloneSet, pod *corev1.Pod, restart *RestartContainers) (*v1alpha1.ContainerRecreateRequest, error) {
+	if !isOwnedBy(pod.ObjectMeta, cloneSet.UID) {
+		return nil, errors.NewBadRequest(fmt.Sprintf("pod %s is not owned by cloneset %s", pod.Name, cloneSet.Name))
+	}
+
+	names := restart.Containers
+	if len(names) == 0 {
+		for _, container := range pod.Spec.Containers {
+			names = append(names, container.Name)
+		}
+	}
+
+	containers := make([]v1alpha1.ContainerRecreateRequestContainer, 0, len(names))
+	for _, containerName := range names {
+		found := false
+		for _, container := range pod.Spec.Containers {
+			if container.Name == containerName {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errors.NewBadRequest(fmt.Sprintf("container %s not found in pod %s", containerName, pod.Name))
+		}
+		containers = append(containers, v1alpha1.ContainerRecreateRequestContainer{Name: containerName})
+	}
+
+	return &v1alpha1.ContainerRecreateRequest{
+		ObjectMeta: v1.ObjectMeta{
+			GenerateName: pod.Name + "-",
+			Namespace:    pod.Namespace,
+		},
+		Spec: v1alpha1.ContainerRecreateRequestSpec{
+			PodName:                 pod.Name,
+			Containers:              containers,
+			Strategy:                restart.Strategy,
+			ActiveDeadlineSeconds:   restart.ActiveDeadlineSeconds,
+			TTLSecondsAfterFinished: restart.TTLSecondsAfterFinished,
+		},
+	}, nil
+}
+
+// podRestarts returns the ContainerRecreateRequests of the pod, newest first. Requests labeled with the
+// UID of an earlier pod of the same name are left out.
+func podRestarts(pod *corev1.Pod, containerRecreateRequests []*v1alpha1.ContainerRecreateRequest) []PodRestart {
+	restarts := make([]PodRestart, 0)
+	for _, containerRecreateRequest := range containerRecreateRequests {
+		if containerRecreateRequest.Spec.PodName != pod.Name {
+			continue
+		}
+		if uid, ok := containerRecreateRequest.Labels[v1alpha1.ContainerRecreateRequestPodUIDKey]; ok && uid != string(pod.UID) {
+			continue
+		}
+		restarts = append(restarts, podRestart(containerRecreateRequest))
+	}
+	sort.Slice(restarts, func(i, j int) bool {
+		return restarts[j].CreationTime.Before(&restarts[i].CreationTime)
+	})
+	return restarts
+}
+
+// podRestart reports the phase of every container of the request, Pending until kruise-daemon reports
+// a state for it.
+func podRestart(containerRecreateRequest *v1alpha1.ContainerRecreateRequest) PodRestart {
+	states := make(map[string]v1alpha1.ContainerRecreateRequestContainerRecreateState)
+	for _, state := range containerRecreateRequest.Status.ContainerRecreateStates {
+		states[state.Name] = state
+	}
+
+	phase := containerRecreateRequest.Status.Phase
+	if phase == "" {
+		phase = v1alpha1.ContainerRecreateRequestPending
+	}
+	restart := PodRestart{
+		Name:           containerRecreateRequest.Name,
+		Phase:          phase,
+		Message:        containerRecreateRequest.Status.Message,
+		CreationTime:   containerRecreateRequest.CreationTimestamp,
+		CompletionTime: containerRecreateRequest.Status.CompletionTime,
+		Containers:     make([]ContainerRestart, 0, len(containerRecreateRequest.Spec.Containers)),
+	}
+	for _, container := range containerRecreateRequest.Spec.Containers {
+		containerRestart := ContainerRestart{
+			Name:  container.Name,
+			Phase: v1alpha1.ContainerRecreateRequestPending,
+		}
+		if state, ok := states[container.Name]; ok {
+			containerRestart.Phase = state.Phase
+			containerRestart.IsKilled = state.IsKilled
+			containerRestart.Message = state.Message
+		}
+		restart.Containers = append(restart.Containers, containerRestart)
+	}
+	return restart
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openkruise/kruise-api/apps/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestNewContainerRecreateRequest(t *testing.T) {
+	cloneSet := &v1alpha1.CloneSet{ObjectMeta: v1.ObjectMeta{Name: "web", Namespace: "default", UID: "cloneset-uid"}}
+	pod := func(owner string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: v1.ObjectMeta{
+				Name:            "web-1",
+				Namespace:       "default",
+				OwnerReferences: []v1.OwnerReference{{UID: types.UID(owner)}},
+			},
+			Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "init"}},
+				Containers:     []corev1.Container{{Name: "main"}, {Name: "sidecar"}},
+			},
+		}
+	}
+	ttl := int32(600)
+	strategy := &v1alpha1.ContainerRecreateRequestStrategy{OrderedRecreate: true}
+
+	tests := []struct {
+		name       string
+		pod        *corev1.Pod
+		containers []string
+		want       []string
+		wantErr    bool
+	}{
+		{
+			name: "all containers by default",
+			pod:  pod("cloneset-uid"),
+			want: []string{"main", "sidecar"},
+		},
+		{
+			name:       "selected containers",
+			pod:        pod("cloneset-uid"),
+			containers: []string{"sidecar"},
+			want:       []string{"sidecar"},
+		},
+		{
+			name:       "unknown container",
+			pod:        pod("cloneset-uid"),
+			containers: []string{"main", "debug"},
+			wantErr:    true,
+		},
+		{
+			name:       "init containers cannot be restarted",
+			pod:        pod("cloneset-uid"),
+			containers: []string{"init"},
+			wantErr:    true,
+		},
+		{
+			name:    "pod owned by another workload",
+			pod:     pod("other-uid"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restart := &RestartContainers{Containers: tt.containers, Strategy: strategy, TTLSecondsAfterFinished: &ttl}
+			containerRecreateRequest, err := newContainerRecreateRequest(cloneSet, tt.pod, restart)
+			if tt.wantErr {
+				assert.True(t, errors.IsBadRequest(err), "unexpected error %v", err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "web-1-", containerRecreateRequest.GenerateName)
+			assert.Equal(t, "default", containerRecreateRequest.Namespace)
+			assert.Equal(t, "web-1", containerRecreateRequest.Spec.PodName)
+			assert.Equal(t, strategy, containerRecreateRequest.Spec.Strategy)
+			assert.Equal(t, &ttl, containerRecreateRequest.Spec.TTLSecondsAfterFinished)
+			var names []string
+			for _, container := range containerRecreateRequest.Spec.Containers {
+				names = append(names, container.Name)
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
+
+func TestPodRestarts(t *testing.T) {
+	now := time.Now()
+	completed := v1.NewTime(now)
+	pod := &corev1.Pod{ObjectMeta: v1.ObjectMeta{Name: "web-1", UID: "pod-uid"}}
+	containerRecreateRequest := func(name, podName, podUID string, age time.Duration, status v1alpha1.ContainerRecreateRequestStatus) *v1alpha1.ContainerRecreateRequest {
+		containerRecreateRequest := &v1alpha1.ContainerRecreateRequest{
+			ObjectMeta: v1.ObjectMeta{Name: name, CreationTimestamp: v1.NewTime(now.Add(-age))},
+			Spec: v1alpha1.ContainerRecreateRequestSpec{
+				PodName:    podName,
+				Containers: []v1alpha1.ContainerRecreateRequestContainer{{Name: "main"}, {Name: "sidecar"}},
+			},
+			Status: status,
+		}
+		if podUID != "" {
+			containerRecreateRequest.Labels = map[string]string{v1alpha1.ContainerRecreateRequestPodUIDKey: podUID}
+		}
+		return containerRecreateRequest
+	}
+
+	restarts := podRestarts(pod, []*v1alpha1.ContainerRecreateRequest{
+		containerRecreateRequest("done", "web-1", "pod-uid", time.Hour, v1alpha1.ContainerRecreateRequestStatus{
+			Phase:          v1alpha1.ContainerRecreateRequestCompleted,
+			CompletionTime: &completed,
+			ContainerRecreateStates: []v1alpha1.ContainerRecreateRequestContainerRecreateState{
+				{Name: "main", Phase: v1alpha1.ContainerRecreateRequestSucceeded, IsKilled: true},
+				{Name: "sidecar", Phase: v1alpha1.ContainerRecreateRequestFailed, Message: "container not found"},
+			},
+		}),
+		containerRecreateRequest("recreating", "web-1", "pod-uid", time.Minute, v1alpha1.ContainerRecreateRequestStatus{
+			Phase: v1alpha1.ContainerRecreateRequestRecreating,
+			ContainerRecreateStates: []v1alpha1.ContainerRecreateRequestContainerRecreateState{
+				{Name: "main", Phase: v1alpha1.ContainerRecreateRequestRecreating, IsKilled: true},
+			},
+		}),
+		containerRecreateRequest("new", "web-1", "", time.Second, v1alpha1.ContainerRecreateRequestStatus{}),
+		containerRecreateRequest("earlier-pod", "web-1", "old-uid", time.Minute, v1alpha1.ContainerRecreateRequestStatus{}),
+		containerRecreateRequest("other-pod", "web-2", "", time.Minute, v1alpha1.ContainerRecreateRequestStatus{}),
+	})
+
+	pending := func(name string) ContainerRestart {
+		return ContainerRestart{Name: name, Phase: v1alpha1.ContainerRecreateRequestPending}
+	}
+	var names []string
+	for _, restart := range restarts {
+		names = append(names, restart.Name)
+	}
+	assert.Equal(t, []string{"new", "recreating", "done"}, names)
+
+	assert.Equal(t, v1alpha1.ContainerRecreateRequestPending, restarts[0].Phase)
+	assert.Equal(t, []ContainerRestart{pending("main"), pending("sidecar")}, restarts[0].Containers)
+
+	assert.Equal(t, v1alpha1.ContainerRecreateRequestRecreating, restarts[1].Phase)
+	assert.Equal(t, []ContainerRestart{
+		{Name: "main", Phase: v1alpha1.ContainerRecreateRequestRecreating, IsKilled: true},
+		pending("sidecar"),
+	}, restarts[1].Containers)
+
+	assert.Equal(t, v1alpha1.ContainerRecreateRequestCompleted, restarts[2].Phase)
+	assert.Equal(t, &completed, restarts[2].CompletionTime)
+	assert.Equal(t, []ContainerRestart{
+		{Name: "main", Phase: v1alpha1.ContainerRecreateRequestSucceeded, IsKilled: true},
+		{Name: "sidecar", Phase: v1alpha1.ContainerRecreateRequestFailed, Message: "container not found"},
+	}, restarts[2].Containers)
+}
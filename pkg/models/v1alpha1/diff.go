@@ -0,0 +1,207 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"gomodules.xyz/jsonpatch/v2"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	liveObject     = "live"
+	proposedObject = "proposed"
+)
+
+// ResourceDiff is the change from one version of a workload to another, as a JSON patch and as a
+// unified diff of the indented JSON.
+type ResourceDiff struct {
+	From        string                         `json:"from" description:"revision name, or live for the live object"`
+	To          string                         `json:"to" description:"revision name, or proposed for the proposed object"`
+	Patch       []jsonpatch.JsonPatchOperation `json:"patch" description:"JSON patch (RFC 6902) from the old version to the new one"`
+	UnifiedDiff string                         `json:"unifiedDiff" description:"unified diff of the old and the new version"`
+}
+
+// DiffCloneSetRevisions diffs two revisions of the CloneSet by revision number. to defaults to the
+// update revision and from to the revision before to.
+func (c *operator) DiffCloneSetRevisions(namespace, name string, from, to int64) (*ResourceDiff, error) {
+	cloneSet, err := c.kruiseclientset.AppsV1alpha1().CloneSets(namespace).Get(context.Background(), name, v1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	revisions, err := ListCloneSetRevisions(context.Background(), c.kubernetesclientset, cloneSet)
+	if err != nil {
+		return nil, err
+	}
+	return diffRevisions(name, revisions, from, to, cloneSet.Status.UpdateRevision)
+}
+
+// DiffSidecarSetRevisions diffs two revisions of the SidecarSet by revision number. to defaults to
+// the latest revision and from to the revision before to.
+func (c *operator) DiffSidecarSetRevisions(name string, from, to int64) (*ResourceDiff, error) {
+	sidecarSet, err := c.kruiseclientset.AppsV1alpha1().SidecarSets().Get(context.Background(), name, v1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	revisions, err := ListSidecarSetRevisions(context.Background(), c.kubernetesclientset, sidecarSet)
+	if err != nil {
+		return nil, err
+	}
+	return diffRevisions(name, revisions, from, to, sidecarSet.Status.LatestRevision)
+}
+
+// DiffResource diffs the live object with the result of a dry-run update with the proposed one, so
+// the defaults and the mutations of admission webhooks are applied to both sides of the diff. Fields
+// set by the server, like the status, are left out.
+func (c *operator) DiffResource(namespace, resource, name string, proposed runtime.Object) (*ResourceDiff, error) {
+	live, err := c.Get(namespace, resource, name)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := c.update(namespace, resource, name, proposed, v1.UpdateOptions{DryRun: []string{v1.DryRunAll}})
+	if err != nil {
+		return nil, err
+	}
+	liveData, err := userFields(live)
+	if err != nil {
+		return nil, err
+	}
+	updatedData, err := userFields(updated)
+	if err != nil {
+		return nil, err
+	}
+	return diffObjects(liveObject, proposedObject, liveData, updatedData)
+}
+
+// diffRevisions picks the revisions to diff, latest is the name of the revision to defaults to.
+func diffRevisions(name string, revisions []*appsv1.ControllerRevision, from, to int64, latest string) (*ResourceDiff, error) {
+	if from < 0 || to < 0 {
+		return nil, errors.NewBadRequest("revision numbers must not be negative")
+	}
+
+	var toRevision, fromRevision *appsv1.ControllerRevision
+	for _, revision := range revisions {
+		if (to == 0 && revision.Name == latest) || (to != 0 && revision.Revision == to) {
+			toRevision = revision
+		}
+	}
+	if toRevision == nil {
+		if to == 0 {
+			return nil, errors.NewNotFound(appsv1.Resource("controllerrevisions"), latest)
+		}
+		return nil, errors.NewNotFound(appsv1.Resource("controllerrevisions"), fmt.Sprintf("%s revision %d", name, to))
+	}
+	// revisions are sorted oldest first
+	for _, revision := range revisions {
+		if (from == 0 && revision.Revision < toRevision.Revision) || (from != 0 && revision.Revision == from) {
+			fromRevision = revision
+		}
+	}
+	if fromRevision == nil {
+		if from == 0 {
+			return nil, errors.NewBadRequest(fmt.Sprintf("%s has no revision before %s", name, toRevision.Name))
+		}
+		return nil, errors.NewNotFound(appsv1.Resource("controllerrevisions"), fmt.Sprintf("%s revision %d", name, from))
+	}
+
+	fromData, err := revisionData(fromRevision)
+	if err != nil {
+		return nil, err
+	}
+	toData, err := revisionData(toRevision)
+	if err != nil {
+		return nil, err
+	}
+	return diffObjects(fromRevision.Name, toRevision.Name, fromData, toData)
+}
+
+func diffObjects(from, to string, fromData, toData interface{}) (*ResourceDiff, error) {
+	fromJSON, err := json.MarshalIndent(fromData, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	toJSON, err := json.MarshalIndent(toData, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	patch, err := jsonpatch.CreatePatch(fromJSON, toJSON)
+	if err != nil {
+		return nil, err
+	}
+	unifiedDiff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(fromJSON) + "\n"),
+		B:        difflib.SplitLines(string(toJSON) + "\n"),
+		FromFile: from,
+		ToFile:   to,
+		Context:  3,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ResourceDiff{From: from, To: to, Patch: patch, UnifiedDiff: unifiedDiff}, nil
+}
+
+// revisionData decodes the data of a ControllerRevision without the $patch directives kruise stores
+// it with.
+func revisionData(revision *appsv1.ControllerRevision) (map[string]interface{}, error) {
+	data := map[string]interface{}{}
+	if err := json.Unmarshal(revision.Data.Raw, &data); err != nil {
+		return nil, fmt.Errorf("invalid data of revision %s: %v", revision.Name, err)
+	}
+	stripPatchDirectives(data)
+	return data, nil
+}
+
+func stripPatchDirectives(obj interface{}) {
+	switch obj := obj.(type) {
+	case map[string]interface{}:
+		delete(obj, "$patch")
+		for _, value := range obj {
+			stripPatchDirectives(value)
+		}
+	case []interface{}:
+		for _, value := range obj {
+			stripPatchDirectives(value)
+		}
+	}
+}
+
+// userFields returns the object as JSON fields without the status, the type and the metadata managed
+// by the server.
+func userFields(obj runtime.Object) (map[string]interface{}, error) {
+	data, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	delete(data, "apiVersion")
+	delete(data, "kind")
+	delete(data, "status")
+	if metadata, ok := data["metadata"].(map[string]interface{}); ok {
+		for _, field := range []string{"uid", "resourceVersion", "generation", "creationTimestamp", "managedFields", "selfLink"} {
+			delete(metadata, field)
+		}
+	}
+	return data, nil
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/openkruise/kruise-api/apps/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"gomodules.xyz/jsonpatch/v2"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDiffRevisions(t *testing.T) {
+	revisions := []*appsv1.ControllerRevision{
+		newRevision("web-v1", 1, "nginx:1.23"),
+		newRevision("web-v2", 2, "nginx:1.24"),
+		newRevision("web-v3", 3, "nginx:1.25"),
+	}
+
+	diff, err := diffRevisions("web", revisions, 0, 0, "web-v3")
+	assert.NoError(t, err)
+	assert.Equal(t, "web-v2", diff.From)
+	assert.Equal(t, "web-v3", diff.To)
+	assert.Equal(t, []jsonpatch.JsonPatchOperation{
+		jsonpatch.NewOperation("replace", "/spec/template/spec/containers/0/image", "nginx:1.25"),
+	}, diff.Patch)
+	assert.Contains(t, diff.UnifiedDiff, "--- web-v2\n+++ web-v3\n")
+	assert.Contains(t, diff.UnifiedDiff, `-            "image": "nginx:1.24",`)
+	assert.Contains(t, diff.UnifiedDiff, `+            "image": "nginx:1.25",`)
+	assert.NotContains(t, diff.UnifiedDiff, "$patch")
+
+	diff, err = diffRevisions("web", revisions, 3, 1, "web-v3")
+	assert.NoError(t, err)
+	assert.Equal(t, "web-v3", diff.From)
+	assert.Equal(t, "web-v1", diff.To)
+
+	_, err = diffRevisions("web", revisions, 0, 1, "web-v3")
+	assert.Error(t, err)
+	_, err = diffRevisions("web", revisions, 4, 0, "web-v3")
+	assert.Error(t, err)
+}
+
+func TestUserFields(t *testing.T) {
+	cloneSet := &v1alpha1.CloneSet{
+		TypeMeta: v1.TypeMeta{Kind: "CloneSet", APIVersion: "apps.kruise.io/v1alpha1"},
+		ObjectMeta: v1.ObjectMeta{
+			Name:            "web",
+			Namespace:       "default",
+			Labels:          map[string]string{"app": "web"},
+			ResourceVersion: "42",
+			Generation:      3,
+		},
+		Status: v1alpha1.CloneSetStatus{Replicas: 3},
+	}
+
+	fields, err := userFields(cloneSet)
+	assert.NoError(t, err)
+	assert.NotContains(t, fields, "kind")
+	assert.NotContains(t, fields, "status")
+	assert.Equal(t, map[string]interface{}{
+		"name":      "web",
+		"namespace": "default",
+		"labels":    map[string]interface{}{"app": "web"},
+	}, fields["metadata"])
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/api"
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/constants"
+	"github.com/openkruise/kruise-api/apps/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	ephemeralContainerWaiting    = "Waiting"
+	ephemeralContainerRunning    = "Running"
+	ephemeralContainerTerminated = "Terminated"
+	ephemeralContainerUnknown    = "Unknown"
+)
+
+// EphemeralJobPod is a pod selected by an EphemeralJob and the ephemeral containers injected into it.
+type EphemeralJobPod struct {
+	Name       string                    `json:"name" description:"name of the pod"`
+	NodeName   string                    `json:"nodeName,omitempty" description:"node the pod is running on"`
+	Containers []EphemeralContainerState `json:"containers" description:"ephemeral containers injected by the ephemeraljob, empty if not injected yet"`
+}
+
+// EphemeralContainerState is the state of an ephemeral container injected by an EphemeralJob.
+type EphemeralContainerState struct {
+	Name     string `json:"name" description:"name of the ephemeral container"`
+	State    string `json:"state" description:"Waiting, Running, Terminated or Unknown"`
+	Reason   string `json:"reason,omitempty" description:"reason of the waiting or terminated state"`
+	Message  string `json:"message,omitempty" description:"message of the waiting or terminated state"`
+	ExitCode *int32 `json:"exitCode,omitempty" description:"exit code of the terminated container"`
+}
+
+// ListEphemeralJobPods lists the pods selected by the EphemeralJob with the states of
+// the ephemeral containers it injected into them.
+func (c *operator) ListEphemeralJobPods(namespace, name string) (*api.ListResult, error) {
+	obj, err := c.resourceGetter.Get(constants.EphemeralJobType, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	ephemeralJob := obj.(*v1alpha1.EphemeralJob)
+
+	pods, err := c.ListPods(namespace, constants.EphemeralJobType, name)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]interface{}, 0, len(pods.Items))
+	for _, item := range pods.Items {
+		pod := item.(*corev1.Pod)
+		items = append(items, EphemeralJobPod{
+			Name:       pod.Name,
+			NodeName:   pod.Spec.NodeName,
+			Containers: ephemeralContainerStates(ephemeralJob, pod),
+		})
+	}
+	return api.NewListResult(items, len(items)), nil
+}
+
+// ephemeralContainerStates returns the states of the ephemeral containers of the pod that were
+// injected by the EphemeralJob, recognized by the job UID in their environment.
+func ephemeralContainerStates(ephemeralJob *v1alpha1.EphemeralJob, pod *corev1.Pod) []EphemeralContainerState {
+	statuses := make(map[string]corev1.ContainerStatus)
+	for _, status := range pod.Status.EphemeralContainerStatuses {
+		statuses[status.Name] = status
+	}
+
+	states := make([]EphemeralContainerState, 0)
+	for _, container := range pod.Spec.EphemeralContainers {
+		if !injectedBy(ephemeralJob, container) {
+			continue
+		}
+		state := EphemeralContainerState{
+			Name:  container.Name,
+			State: ephemeralContainerUnknown,
+		}
+		if status, ok := statuses[container.Name]; ok {
+			switch {
+			case status.State.Running != nil:
+				state.State = ephemeralContainerRunning
+			case status.State.Terminated != nil:
+				state.State = ephemeralContainerTerminated
+				state.Reason = status.State.Terminated.Reason
+				state.Message = status.State.Terminated.Message
+				state.ExitCode = &status.State.Terminated.ExitCode
+			case status.State.Waiting != nil:
+				state.State = ephemeralContainerWaiting
+				state.Reason = status.State.Waiting.Reason
+				state.Message = status.State.Waiting.Message
+			}
+		}
+		states = append(states, state)
+	}
+	return states
+}
+
+func injectedBy(ephemeralJob *v1alpha1.EphemeralJob, container corev1.EphemeralContainer) bool {
+	for _, env := range container.Env {
+		if env.Name == v1alpha1.EphemeralContainerEnvKey && env.Value == string(ephemeralJob.UID) {
+			return true
+		}
+	}
+	return false
+}
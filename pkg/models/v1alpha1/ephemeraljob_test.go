@@ -0,0 +1,104 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/openkruise/kruise-api/apps/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestEphemeralContainerStates(t *testing.T) {
+	ephemeralJob := &v1alpha1.EphemeralJob{ObjectMeta: v1.ObjectMeta{Name: "debug", UID: "job-uid"}}
+	container := func(name, jobUID string) corev1.EphemeralContainer {
+		return corev1.EphemeralContainer{EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+			Name: name,
+			Env:  []corev1.EnvVar{{Name: v1alpha1.EphemeralContainerEnvKey, Value: jobUID}},
+		}}
+	}
+	exitCode := int32(137)
+	tests := []struct {
+		name       string
+		containers []corev1.EphemeralContainer
+		statuses   []corev1.ContainerStatus
+		want       []EphemeralContainerState
+	}{
+		{
+			name:       "running",
+			containers: []corev1.EphemeralContainer{container("debug-1", "job-uid")},
+			statuses: []corev1.ContainerStatus{{
+				Name:  "debug-1",
+				State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			}},
+			want: []EphemeralContainerState{{Name: "debug-1", State: ephemeralContainerRunning}},
+		},
+		{
+			name:       "terminated",
+			containers: []corev1.EphemeralContainer{container("debug-1", "job-uid")},
+			statuses: []corev1.ContainerStatus{{
+				Name: "debug-1",
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+					ExitCode: exitCode,
+					Reason:   "Error",
+					Message:  "killed",
+				}},
+			}},
+			want: []EphemeralContainerState{{Name: "debug-1", State: ephemeralContainerTerminated, Reason: "Error", Message: "killed", ExitCode: &exitCode}},
+		},
+		{
+			name:       "waiting",
+			containers: []corev1.EphemeralContainer{container("debug-1", "job-uid")},
+			statuses: []corev1.ContainerStatus{{
+				Name:  "debug-1",
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "back-off pulling image"}},
+			}},
+			want: []EphemeralContainerState{{Name: "debug-1", State: ephemeralContainerWaiting, Reason: "ImagePullBackOff", Message: "back-off pulling image"}},
+		},
+		{
+			name:       "injected without a status yet",
+			containers: []corev1.EphemeralContainer{container("debug-1", "job-uid")},
+			want:       []EphemeralContainerState{{Name: "debug-1", State: ephemeralContainerUnknown}},
+		},
+		{
+			name: "not injected yet",
+			want: []EphemeralContainerState{},
+		},
+		{
+			name:       "only containers of the ephemeral job",
+			containers: []corev1.EphemeralContainer{container("other", "other-uid"), container("debug-1", "job-uid"), {EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: "kubectl-debug"}}},
+			statuses: []corev1.ContainerStatus{
+				{Name: "other", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+				{Name: "debug-1", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+				{Name: "kubectl-debug", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+			},
+			want: []EphemeralContainerState{{Name: "debug-1", State: ephemeralContainerRunning}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{
+				ObjectMeta: v1.ObjectMeta{Name: "web-1"},
+				Spec:       corev1.PodSpec{EphemeralContainers: tt.containers},
+				Status:     corev1.PodStatus{EphemeralContainerStatuses: tt.statuses},
+			}
+			assert.Equal(t, tt.want, ephemeralContainerStates(ephemeralJob, pod))
+		})
+	}
+}
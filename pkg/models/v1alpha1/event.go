@@ -0,0 +1,167 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"sort"
+
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/api"
+	"github.com/duke-git/lancet/v2/slice"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// WorkloadEvent is an Event of a workload or one of its pods. Events with the same object, type,
+// reason and message are merged into one.
+type WorkloadEvent struct {
+	Type           string                 `json:"type" description:"Normal or Warning"`
+	Reason         string                 `json:"reason" description:"short reason of the event"`
+	Message        string                 `json:"message" description:"human-readable description of the event"`
+	InvolvedObject corev1.ObjectReference `json:"involvedObject" description:"the workload or pod the event is about"`
+	Source         string                 `json:"source,omitempty" description:"component that reported the event"`
+	Count          int32                  `json:"count" description:"number of times the event occurred"`
+	FirstTimestamp v1.Time                `json:"firstTimestamp" description:"time the event first occurred"`
+	LastTimestamp  v1.Time                `json:"lastTimestamp" description:"time the event last occurred"`
+}
+
+// ListWorkloadEvents lists the Events of the workload and of the pods resolved for it by ListPods,
+// oldest first.
+func (c *operator) ListWorkloadEvents(namespace, resource, name string) (*api.ListResult, error) {
+	workload, err := c.resourceGetter.Get(resource, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	workloadMeta, err := meta.Accessor(workload)
+	if err != nil {
+		return nil, err
+	}
+
+	uids := map[types.UID]bool{workloadMeta.GetUID(): true}
+	if slice.Contain(podWorkloadTypes, resource) {
+		pods, err := c.ListPods(namespace, resource, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range pods.Items {
+			uids[item.(*corev1.Pod).UID] = true
+		}
+	}
+
+	var events []*corev1.Event
+	if workloadMeta.GetNamespace() == "" {
+		// the pods of cluster-scoped workloads can be in any namespace
+		events, err = c.eventLister.List(labels.Everything())
+	} else {
+		events, err = c.eventLister.Events(namespace).List(labels.Everything())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	workloadEvents := mergeEvents(events, uids)
+	items := make([]interface{}, 0, len(workloadEvents))
+	for _, event := range workloadEvents {
+		items = append(items, event)
+	}
+	return &api.ListResult{Items: items, TotalItems: len(items)}, nil
+}
+
+// mergeEvents merges the Events about the objects with the uids, sorted by the time they last occurred.
+func mergeEvents(events []*corev1.Event, uids map[types.UID]bool) []*WorkloadEvent {
+	type eventKey struct {
+		uid                        types.UID
+		eventType, reason, message string
+	}
+
+	merged := map[eventKey]*WorkloadEvent{}
+	for _, event := range events {
+		if !uids[event.InvolvedObject.UID] {
+			continue
+		}
+		first, last := eventTimes(event)
+		count := event.Count
+		if event.Series != nil && event.Series.Count > count {
+			count = event.Series.Count
+		}
+		if count == 0 {
+			count = 1
+		}
+
+		key := eventKey{uid: event.InvolvedObject.UID, eventType: event.Type, reason: event.Reason, message: event.Message}
+		if workloadEvent, ok := merged[key]; ok {
+			workloadEvent.Count += count
+			if first.Before(&workloadEvent.FirstTimestamp) {
+				workloadEvent.FirstTimestamp = first
+			}
+			if workloadEvent.LastTimestamp.Before(&last) {
+				workloadEvent.LastTimestamp = last
+			}
+			continue
+		}
+		source := event.Source.Component
+		if source == "" {
+			source = event.ReportingController
+		}
+		merged[key] = &WorkloadEvent{
+			Type:           event.Type,
+			Reason:         event.Reason,
+			Message:        event.Message,
+			InvolvedObject: event.InvolvedObject,
+			Source:         source,
+			Count:          count,
+			FirstTimestamp: first,
+			LastTimestamp:  last,
+		}
+	}
+
+	workloadEvents := make([]*WorkloadEvent, 0, len(merged))
+	for _, workloadEvent := range merged {
+		workloadEvents = append(workloadEvents, workloadEvent)
+	}
+	sort.Slice(workloadEvents, func(i, j int) bool {
+		if !workloadEvents[i].LastTimestamp.Equal(&workloadEvents[j].LastTimestamp) {
+			return workloadEvents[i].LastTimestamp.Before(&workloadEvents[j].LastTimestamp)
+		}
+		if workloadEvents[i].InvolvedObject.Name != workloadEvents[j].InvolvedObject.Name {
+			return workloadEvents[i].InvolvedObject.Name < workloadEvents[j].InvolvedObject.Name
+		}
+		return workloadEvents[i].Reason < workloadEvents[j].Reason
+	})
+	return workloadEvents
+}
+
+// eventTimes returns when the event first and last occurred, events reported through the
+// events.k8s.io API only set the event time and the series.
+func eventTimes(event *corev1.Event) (v1.Time, v1.Time) {
+	first, last := event.FirstTimestamp, event.LastTimestamp
+	if first.IsZero() {
+		first = v1.Time{Time: event.EventTime.Time}
+	}
+	if first.IsZero() {
+		first = event.CreationTimestamp
+	}
+	if event.Series != nil && last.Before(&v1.Time{Time: event.Series.LastObservedTime.Time}) {
+		last = v1.Time{Time: event.Series.LastObservedTime.Time}
+	}
+	if last.IsZero() {
+		last = first
+	}
+	return first, last
+}
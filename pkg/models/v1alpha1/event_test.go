@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestMergeEvents(t *testing.T) {
+	base := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	at := func(minutes int) v1.Time {
+		return v1.NewTime(base.Add(time.Duration(minutes) * time.Minute))
+	}
+	event := func(name string, uid types.UID, reason string, count int32, first, last int) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     v1.ObjectMeta{Name: name, Namespace: "default"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: string(uid), UID: uid},
+			Type:           corev1.EventTypeWarning,
+			Reason:         reason,
+			Message:        reason + " of " + string(uid),
+			Source:         corev1.EventSource{Component: "kubelet"},
+			Count:          count,
+			FirstTimestamp: at(first),
+			LastTimestamp:  at(last),
+		}
+	}
+	seriesEvent := &corev1.Event{
+		ObjectMeta:          v1.ObjectMeta{Name: "web.series", Namespace: "default", CreationTimestamp: at(0)},
+		InvolvedObject:      corev1.ObjectReference{Kind: "CloneSet", Name: "web", UID: "web"},
+		Type:                corev1.EventTypeNormal,
+		Reason:              "SuccessfulCreate",
+		Message:             "create pods",
+		ReportingController: "cloneset-controller",
+		EventTime:           v1.NewMicroTime(base.Add(time.Minute)),
+		Series:              &corev1.EventSeries{Count: 3, LastObservedTime: v1.NewMicroTime(base.Add(5 * time.Minute))},
+	}
+
+	events := mergeEvents([]*corev1.Event{
+		event("web-a.1", "web-a", "BackOff", 4, 2, 8),
+		event("web-a.2", "web-a", "BackOff", 2, 1, 6),
+		event("web-b.1", "web-b", "Unhealthy", 1, 3, 3),
+		event("other.1", "other", "BackOff", 1, 0, 10),
+		seriesEvent,
+	}, map[types.UID]bool{"web": true, "web-a": true, "web-b": true})
+
+	assert.Len(t, events, 3)
+	assert.Equal(t, &WorkloadEvent{
+		Type:           corev1.EventTypeWarning,
+		Reason:         "Unhealthy",
+		Message:        "Unhealthy of web-b",
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "web-b", UID: "web-b"},
+		Source:         "kubelet",
+		Count:          1,
+		FirstTimestamp: at(3),
+		LastTimestamp:  at(3),
+	}, events[0])
+	assert.Equal(t, "cloneset-controller", events[1].Source)
+	assert.Equal(t, int32(3), events[1].Count)
+	assert.Equal(t, at(1), events[1].FirstTimestamp)
+	assert.Equal(t, at(5), events[1].LastTimestamp)
+	assert.Equal(t, "BackOff", events[2].Reason)
+	assert.Equal(t, int32(6), events[2].Count)
+	assert.Equal(t, at(1), events[2].FirstTimestamp)
+	assert.Equal(t, at(8), events[2].LastTimestamp)
+}
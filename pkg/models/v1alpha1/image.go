@@ -0,0 +1,225 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/constants"
+	"github.com/openkruise/kruise-api/apps/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/util/retry"
+)
+
+// SetCloneSetImages sets container images of a CloneSet by container name.
+type SetCloneSetImages struct {
+	Images map[string]string `json:"images" description:"new images keyed by container or init container name"`
+	// UpdateType is the update strategy type set on the CloneSet. It defaults to the type of the CloneSet,
+	// or InPlaceIfPossible when the CloneSet has none.
+	UpdateType v1alpha1.CloneSetUpdateStrategyType `json:"updateType,omitempty" description:"ReCreate, InPlaceIfPossible or InPlaceOnly, defaults to the type of the cloneset or InPlaceIfPossible"`
+}
+
+// CloneSetImageUpdate is the CloneSet with its new images and how its pods will be updated. Only the
+// pods beyond the partition are updated, the others are reported as held back.
+type CloneSetImageUpdate struct {
+	CloneSet      *v1alpha1.CloneSet `json:"cloneSet" description:"the updated cloneset"`
+	InPlacePods   []string           `json:"inPlacePods" description:"pods that will be updated in place"`
+	RecreatePods  []string           `json:"recreatePods" description:"pods that will be deleted and created again"`
+	UnchangedPods []string           `json:"unchangedPods" description:"pods that already run the new template"`
+	HeldPods      []string           `json:"heldPods" description:"pods kept at their revision by the partition"`
+}
+
+// SetCloneSetImages updates container images of the CloneSet and reports which pods are updated in
+// place, compared with the templates of the revisions the pods were created from.
+func (c *operator) SetCloneSetImages(namespace, name string, images *SetCloneSetImages) (*CloneSetImageUpdate, error) {
+	if len(images.Images) == 0 {
+		return nil, errors.NewBadRequest("images must not be empty")
+	}
+	switch images.UpdateType {
+	case "", v1alpha1.RecreateCloneSetUpdateStrategyType, v1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType, v1alpha1.InPlaceOnlyCloneSetUpdateStrategyType:
+	default:
+		return nil, errors.NewBadRequest(fmt.Sprintf("unknown update type %s", images.UpdateType))
+	}
+
+	update := &CloneSetImageUpdate{}
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cloneSet, err := c.kruiseclientset.AppsV1alpha1().CloneSets(namespace).Get(context.Background(), name, v1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		updated := cloneSet.DeepCopy()
+		if err = setImages(&updated.Spec.Template, images.Images); err != nil {
+			return err
+		}
+		updateType := cloneSetUpdateType(cloneSet, images.UpdateType)
+		updated.Spec.UpdateStrategy.Type = updateType
+
+		pods, err := c.ListPods(namespace, constants.CloneSetType, name)
+		if err != nil {
+			return err
+		}
+		revisions, err := ListCloneSetRevisions(context.Background(), c.kubernetesclientset, cloneSet)
+		if err != nil {
+			return err
+		}
+		owned := make([]*corev1.Pod, 0, len(pods.Items))
+		for _, item := range pods.Items {
+			if pod := item.(*corev1.Pod); isOwnedBy(pod.ObjectMeta, cloneSet.UID) {
+				owned = append(owned, pod)
+			}
+		}
+		classifyPods(update, owned, revisions, updated, updateType)
+		if updateType == v1alpha1.InPlaceOnlyCloneSetUpdateStrategyType && len(update.RecreatePods) > 0 {
+			return errors.NewBadRequest(fmt.Sprintf("pods %s cannot be updated in place", strings.Join(update.RecreatePods, ", ")))
+		}
+
+		update.CloneSet, err = c.kruiseclientset.AppsV1alpha1().CloneSets(namespace).Update(context.Background(), updated, v1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return update, nil
+}
+
+// classifyPods sorts the pods of the CloneSet by how they are updated to its template. The partition
+// keeps that many pods at their revision. Like kruise, pods that are not ready are updated first, the
+// held back pods are among the ready ones.
+func classifyPods(update *CloneSetImageUpdate, pods []*corev1.Pod, revisions []*appsv1.ControllerRevision, cloneSet *v1alpha1.CloneSet, updateType v1alpha1.CloneSetUpdateStrategyType) {
+	update.InPlacePods, update.RecreatePods, update.UnchangedPods, update.HeldPods = []string{}, []string{}, []string{}, []string{}
+
+	pending := make([]*corev1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if podUpdate(pod, revisions, &cloneSet.Spec.Template, updateType) == podUnchanged {
+			update.UnchangedPods = append(update.UnchangedPods, pod.Name)
+			continue
+		}
+		pending = append(pending, pod)
+	}
+	sort.SliceStable(pending, func(i, j int) bool {
+		if readyI, readyJ := podConditionStatus(pending[i], corev1.PodReady) == corev1.ConditionTrue, podConditionStatus(pending[j], corev1.PodReady) == corev1.ConditionTrue; readyI != readyJ {
+			return !readyI
+		}
+		return pending[i].Name < pending[j].Name
+	})
+
+	replicas := int32(1)
+	if cloneSet.Spec.Replicas != nil {
+		replicas = *cloneSet.Spec.Replicas
+	}
+	partition := 0
+	if cloneSet.Spec.UpdateStrategy.Partition != nil {
+		partition, _ = intstr.GetScaledValueFromIntOrPercent(cloneSet.Spec.UpdateStrategy.Partition, int(replicas), true)
+	}
+	updateCount := int(replicas) - partition - len(update.UnchangedPods)
+
+	for i, pod := range pending {
+		switch {
+		case i >= updateCount:
+			update.HeldPods = append(update.HeldPods, pod.Name)
+		case podUpdate(pod, revisions, &cloneSet.Spec.Template, updateType) == podInPlace:
+			update.InPlacePods = append(update.InPlacePods, pod.Name)
+		default:
+			update.RecreatePods = append(update.RecreatePods, pod.Name)
+		}
+	}
+	sort.Strings(update.InPlacePods)
+	sort.Strings(update.RecreatePods)
+	sort.Strings(update.UnchangedPods)
+	sort.Strings(update.HeldPods)
+}
+
+// cloneSetUpdateType is the requested update type, or the type of the CloneSet when none is requested,
+// so that an existing ReCreate or InPlaceOnly strategy is kept.
+func cloneSetUpdateType(cloneSet *v1alpha1.CloneSet, requested v1alpha1.CloneSetUpdateStrategyType) v1alpha1.CloneSetUpdateStrategyType {
+	if requested != "" {
+		return requested
+	}
+	if cloneSet.Spec.UpdateStrategy.Type != "" {
+		return cloneSet.Spec.UpdateStrategy.Type
+	}
+	return v1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType
+}
+
+const (
+	podUnchanged = "Unchanged"
+	podInPlace   = "InPlace"
+	podRecreate  = "Recreate"
+)
+
+// podUpdate tells how kruise will update the pod to the template: in place if only images of
+// containers differ from the template of the pod's revision, which kruise can patch on the running
+// pod. Init containers only run when a pod starts, so a new init container image recreates the pod.
+func podUpdate(pod *corev1.Pod, revisions []*appsv1.ControllerRevision, template *corev1.PodTemplateSpec, updateType v1alpha1.CloneSetUpdateStrategyType) string {
+	var podTemplate *corev1.PodTemplateSpec
+	for _, revision := range revisions {
+		if IsPodAtRevision(pod, revision.Name) {
+			podTemplate, _ = TemplateFromRevision(revision)
+			break
+		}
+	}
+	if podTemplate == nil {
+		return podRecreate
+	}
+	if equality.Semantic.DeepEqual(podTemplate.Spec, template.Spec) {
+		return podUnchanged
+	}
+	if updateType == v1alpha1.RecreateCloneSetUpdateStrategyType {
+		return podRecreate
+	}
+	if equality.Semantic.DeepEqual(withoutImages(podTemplate.Spec), withoutImages(template.Spec)) {
+		return podInPlace
+	}
+	return podRecreate
+}
+
+func setImages(template *corev1.PodTemplateSpec, images map[string]string) error {
+	found := map[string]bool{}
+	for _, containers := range [][]corev1.Container{template.Spec.InitContainers, template.Spec.Containers} {
+		for i := range containers {
+			if image, ok := images[containers[i].Name]; ok {
+				containers[i].Image = image
+				found[containers[i].Name] = true
+			}
+		}
+	}
+	for container, image := range images {
+		if !found[container] {
+			return errors.NewBadRequest(fmt.Sprintf("container %s not found", container))
+		}
+		if image == "" {
+			return errors.NewBadRequest(fmt.Sprintf("image of container %s must not be empty", container))
+		}
+	}
+	return nil
+}
+
+func withoutImages(spec corev1.PodSpec) corev1.PodSpec {
+	spec = *spec.DeepCopy()
+	for i := range spec.Containers {
+		spec.Containers[i].Image = ""
+	}
+	return spec
+}
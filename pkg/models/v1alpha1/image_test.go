@@ -0,0 +1,158 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/openkruise/kruise-api/apps/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestPodUpdate(t *testing.T) {
+	revisions := []*appsv1.ControllerRevision{
+		newRevision("web-5d4f8", 1, "nginx:1.24"),
+	}
+	pod := func(revision string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: v1.ObjectMeta{
+			Name:   "web-" + revision,
+			Labels: map[string]string{appsv1.ControllerRevisionHashLabelKey: revision},
+		}}
+	}
+	template := func(images map[string]string) *corev1.PodTemplateSpec {
+		template, err := TemplateFromRevision(revisions[0])
+		assert.NoError(t, err)
+		assert.NoError(t, setImages(template, images))
+		return template
+	}
+
+	tests := []struct {
+		name       string
+		pod        *corev1.Pod
+		template   *corev1.PodTemplateSpec
+		updateType v1alpha1.CloneSetUpdateStrategyType
+		want       string
+	}{
+		{
+			name:       "container image is updated in place",
+			pod:        pod("web-5d4f8"),
+			template:   template(map[string]string{"main": "nginx:1.25"}),
+			updateType: v1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType,
+			want:       podInPlace,
+		},
+		{
+			name:       "short revision hash label",
+			pod:        pod("5d4f8"),
+			template:   template(map[string]string{"main": "nginx:1.25"}),
+			updateType: v1alpha1.InPlaceOnlyCloneSetUpdateStrategyType,
+			want:       podInPlace,
+		},
+		{
+			name:       "init container image recreates the pod",
+			pod:        pod("web-5d4f8"),
+			template:   template(map[string]string{"init": "busybox:1.36"}),
+			updateType: v1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType,
+			want:       podRecreate,
+		},
+		{
+			name:       "recreate update type",
+			pod:        pod("web-5d4f8"),
+			template:   template(map[string]string{"main": "nginx:1.25"}),
+			updateType: v1alpha1.RecreateCloneSetUpdateStrategyType,
+			want:       podRecreate,
+		},
+		{
+			name:       "pod already at the template",
+			pod:        pod("web-5d4f8"),
+			template:   template(map[string]string{"main": "nginx:1.24"}),
+			updateType: v1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType,
+			want:       podUnchanged,
+		},
+		{
+			name:       "unknown revision",
+			pod:        pod("web-7c9b2"),
+			template:   template(map[string]string{"main": "nginx:1.25"}),
+			updateType: v1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType,
+			want:       podRecreate,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, podUpdate(tt.pod, revisions, tt.template, tt.updateType))
+		})
+	}
+
+	err := setImages(template(nil), map[string]string{"sidecar": "envoy"})
+	assert.Error(t, err)
+}
+
+func TestCloneSetUpdateType(t *testing.T) {
+	cloneSet := &v1alpha1.CloneSet{}
+	assert.Equal(t, v1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType, cloneSetUpdateType(cloneSet, ""))
+	assert.Equal(t, v1alpha1.RecreateCloneSetUpdateStrategyType, cloneSetUpdateType(cloneSet, v1alpha1.RecreateCloneSetUpdateStrategyType))
+
+	cloneSet.Spec.UpdateStrategy.Type = v1alpha1.InPlaceOnlyCloneSetUpdateStrategyType
+	assert.Equal(t, v1alpha1.InPlaceOnlyCloneSetUpdateStrategyType, cloneSetUpdateType(cloneSet, ""))
+	assert.Equal(t, v1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType, cloneSetUpdateType(cloneSet, v1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType))
+}
+
+func TestClassifyPods(t *testing.T) {
+	revisions := []*appsv1.ControllerRevision{
+		newRevision("web-v1", 1, "nginx:1.24"),
+		newRevision("web-v2", 2, "nginx:1.25"),
+	}
+	template, err := TemplateFromRevision(revisions[1])
+	assert.NoError(t, err)
+	pod := func(name, revision string, ready corev1.ConditionStatus) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: v1.ObjectMeta{Name: name, Labels: map[string]string{appsv1.ControllerRevisionHashLabelKey: revision}},
+			Status:     corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}}},
+		}
+	}
+	pods := []*corev1.Pod{
+		pod("web-a", "web-v1", corev1.ConditionTrue),
+		pod("web-b", "web-v1", corev1.ConditionFalse),
+		pod("web-c", "web-v1", corev1.ConditionTrue),
+		pod("web-d", "web-v2", corev1.ConditionTrue),
+	}
+	replicas := int32(4)
+	cloneSet := &v1alpha1.CloneSet{Spec: v1alpha1.CloneSetSpec{Replicas: &replicas, Template: *template}}
+
+	update := &CloneSetImageUpdate{}
+	classifyPods(update, pods, revisions, cloneSet, v1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType)
+	assert.Equal(t, []string{"web-a", "web-b", "web-c"}, update.InPlacePods)
+	assert.Equal(t, []string{}, update.RecreatePods)
+	assert.Equal(t, []string{"web-d"}, update.UnchangedPods)
+	assert.Equal(t, []string{}, update.HeldPods)
+
+	partition := intstr.FromString("50%")
+	cloneSet.Spec.UpdateStrategy.Partition = &partition
+	classifyPods(update, pods, revisions, cloneSet, v1alpha1.RecreateCloneSetUpdateStrategyType)
+	assert.Equal(t, []string{}, update.InPlacePods)
+	assert.Equal(t, []string{"web-b"}, update.RecreatePods)
+	assert.Equal(t, []string{"web-d"}, update.UnchangedPods)
+	assert.Equal(t, []string{"web-a", "web-c"}, update.HeldPods)
+
+	partition = intstr.FromInt(4)
+	classifyPods(update, pods, revisions, cloneSet, v1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType)
+	assert.Equal(t, []string{}, update.InPlacePods)
+	assert.Equal(t, []string{"web-a", "web-b", "web-c"}, update.HeldPods)
+}
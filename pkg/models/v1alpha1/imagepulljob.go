@@ -0,0 +1,234 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/api"
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/constants"
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/utils/sliceutil"
+	"github.com/openkruise/kruise-api/apps/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// PullScopeRunning targets the nodes the workload pods currently run on.
+	PullScopeRunning = "running"
+	// PullScopeSchedulable targets the nodes the pod template can be scheduled to: they match its nodeSelector
+	// and required node affinity, and their NoSchedule and NoExecute taints are tolerated.
+	PullScopeSchedulable = "schedulable"
+)
+
+// PullImages describes the ImagePullJobs created to pre-warm the images of a workload.
+type PullImages struct {
+	Scope            string                    `json:"scope,omitempty" description:"nodes to pull on, running (default) or schedulable"`
+	Parallelism      *intstr.IntOrString       `json:"parallelism,omitempty" description:"number of nodes pulling the image in parallel"`
+	PullPolicy       *v1alpha1.PullPolicy      `json:"pullPolicy,omitempty" description:"timeout and backoff of the image pulling"`
+	CompletionPolicy v1alpha1.CompletionPolicy `json:"completionPolicy,omitempty" description:"completion policy of the imagepulljobs"`
+}
+
+// PullCloneSetImages creates one ImagePullJob for every image of the CloneSet pod template.
+func (c *operator) PullCloneSetImages(namespace, name string, pull *PullImages) (*api.ListResult, error) {
+	obj, err := c.resourceGetter.Get(constants.CloneSetType, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	cloneSet := obj.(*v1alpha1.CloneSet)
+	template := cloneSet.Spec.Template
+
+	selector := &v1alpha1.ImagePullJobNodeSelector{}
+	switch pull.Scope {
+	case "", PullScopeRunning:
+		pods, err := c.ListPods(namespace, constants.CloneSetType, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range pods.Items {
+			pod := item.(*corev1.Pod)
+			if pod.Spec.NodeName != "" && !sliceutil.HasString(selector.Names, pod.Spec.NodeName) {
+				selector.Names = append(selector.Names, pod.Spec.NodeName)
+			}
+		}
+		if len(selector.Names) == 0 {
+			return nil, errors.NewBadRequest(fmt.Sprintf("no pods of cloneset %s are scheduled to a node", name))
+		}
+	case PullScopeSchedulable:
+		nodes, err := c.kubernetesclientset.CoreV1().Nodes().List(context.Background(), v1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector.Names = schedulableNodes(&template.Spec, nodes.Items)
+		if len(selector.Names) == 0 {
+			return nil, errors.NewBadRequest(fmt.Sprintf("no nodes match the scheduling constraints of cloneset %s", name))
+		}
+	default:
+		return nil, errors.NewBadRequest(fmt.Sprintf("unknown scope %s", pull.Scope))
+	}
+
+	var pullSecrets []string
+	for _, secret := range template.Spec.ImagePullSecrets {
+		pullSecrets = append(pullSecrets, secret.Name)
+	}
+
+	containers := make([]corev1.Container, 0, len(template.Spec.InitContainers)+len(template.Spec.Containers))
+	containers = append(containers, template.Spec.InitContainers...)
+	containers = append(containers, template.Spec.Containers...)
+	var images []string
+	for _, container := range containers {
+		if !sliceutil.HasString(images, container.Image) {
+			images = append(images, container.Image)
+		}
+	}
+
+	imagePullJobs := make([]*v1alpha1.ImagePullJob, 0, len(images))
+	for _, image := range images {
+		imagePullJobs = append(imagePullJobs, &v1alpha1.ImagePullJob{
+			ObjectMeta: v1.ObjectMeta{
+				GenerateName:    name + "-",
+				Namespace:       namespace,
+				OwnerReferences: []v1.OwnerReference{*v1.NewControllerRef(cloneSet, v1alpha1.SchemeGroupVersion.WithKind(constants.CloneSetTag))},
+			},
+			Spec: v1alpha1.ImagePullJobSpec{
+				Image:            image,
+				PullSecrets:      pullSecrets,
+				Selector:         selector.DeepCopy(),
+				Parallelism:      pull.Parallelism,
+				PullPolicy:       pull.PullPolicy,
+				CompletionPolicy: pull.CompletionPolicy,
+			},
+		})
+	}
+	items, err := c.createImagePullJobs(namespace, imagePullJobs)
+	if err != nil {
+		return nil, err
+	}
+	return api.NewListResult(items, len(items)), nil
+}
+
+// createImagePullJobs creates all the ImagePullJobs or none: when one fails, the ones created before
+// it are deleted again.
+func (c *operator) createImagePullJobs(namespace string, imagePullJobs []*v1alpha1.ImagePullJob) ([]interface{}, error) {
+	items := make([]interface{}, 0, len(imagePullJobs))
+	for _, imagePullJob := range imagePullJobs {
+		created, err := c.kruiseclientset.AppsV1alpha1().ImagePullJobs(namespace).Create(context.Background(), imagePullJob, v1.CreateOptions{})
+		if err != nil {
+			for _, item := range items {
+				created := item.(*v1alpha1.ImagePullJob)
+				deleteErr := c.kruiseclientset.AppsV1alpha1().ImagePullJobs(namespace).Delete(context.Background(), created.Name, v1.DeleteOptions{})
+				if deleteErr != nil && !errors.IsNotFound(deleteErr) {
+					klog.Errorf("failed to delete imagepulljob %s/%s: %v", namespace, created.Name, deleteErr)
+				}
+			}
+			return nil, err
+		}
+		items = append(items, created)
+	}
+	return items, nil
+}
+
+// schedulableNodes returns the names of the nodes the pod spec can be scheduled to, by its nodeSelector,
+// required node affinity and tolerations. Resources and pod affinity are not taken into account.
+func schedulableNodes(spec *corev1.PodSpec, nodes []corev1.Node) []string {
+	var names []string
+	for i := range nodes {
+		node := &nodes[i]
+		if !labels.SelectorFromSet(spec.NodeSelector).Matches(labels.Set(node.Labels)) {
+			continue
+		}
+		if affinity := spec.Affinity; affinity != nil && affinity.NodeAffinity != nil && affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil &&
+			!matchNodeSelectorTerms(node, affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms) {
+			continue
+		}
+		if !toleratesNode(node, spec.Tolerations) {
+			continue
+		}
+		names = append(names, node.Name)
+	}
+	return names
+}
+
+// matchNodeSelectorTerms reports whether the node matches any of the terms, the requirements of a term must all match.
+func matchNodeSelectorTerms(node *corev1.Node, terms []corev1.NodeSelectorTerm) bool {
+	for _, term := range terms {
+		if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+			// an empty term matches no objects
+			continue
+		}
+		if matchNodeSelectorRequirements(labels.Set(node.Labels), term.MatchExpressions) &&
+			matchNodeSelectorRequirements(fields.Set{"metadata.name": node.Name}, term.MatchFields) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchNodeSelectorRequirements(values map[string]string, requirements []corev1.NodeSelectorRequirement) bool {
+	operators := map[corev1.NodeSelectorOperator]selection.Operator{
+		corev1.NodeSelectorOpIn:           selection.In,
+		corev1.NodeSelectorOpNotIn:        selection.NotIn,
+		corev1.NodeSelectorOpExists:       selection.Exists,
+		corev1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+		corev1.NodeSelectorOpGt:           selection.GreaterThan,
+		corev1.NodeSelectorOpLt:           selection.LessThan,
+	}
+	for _, requirement := range requirements {
+		operator, ok := operators[requirement.Operator]
+		if !ok {
+			return false
+		}
+		r, err := labels.NewRequirement(requirement.Key, operator, requirement.Values)
+		if err != nil || !r.Matches(labels.Set(values)) {
+			return false
+		}
+	}
+	return true
+}
+
+// toleratesNode reports whether the tolerations tolerate the taints that keep pods off the node. An
+// unschedulable node is treated as tainted, like the scheduler does.
+func toleratesNode(node *corev1.Node, tolerations []corev1.Toleration) bool {
+	taints := node.Spec.Taints
+	if node.Spec.Unschedulable {
+		taints = append([]corev1.Taint{{Key: corev1.TaintNodeUnschedulable, Effect: corev1.TaintEffectNoSchedule}}, taints...)
+	}
+	for i := range taints {
+		taint := &taints[i]
+		if taint.Effect != corev1.TaintEffectNoSchedule && taint.Effect != corev1.TaintEffectNoExecute {
+			continue
+		}
+		tolerated := false
+		for j := range tolerations {
+			if tolerations[j].ToleratesTaint(taint) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return false
+		}
+	}
+	return true
+}
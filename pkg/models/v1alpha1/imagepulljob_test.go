@@ -0,0 +1,145 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/openkruise/kruise-api/apps/v1alpha1"
+	kruisefake "github.com/openkruise/kruise-api/client/clientset/versioned/fake"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestSchedulableNodes(t *testing.T) {
+	node := func(name string, nodeLabels map[string]string, taints ...corev1.Taint) corev1.Node {
+		return corev1.Node{
+			ObjectMeta: v1.ObjectMeta{Name: name, Labels: nodeLabels},
+			Spec:       corev1.NodeSpec{Taints: taints},
+		}
+	}
+	cordoned := node("cordoned", map[string]string{"disk": "ssd"})
+	cordoned.Spec.Unschedulable = true
+	nodes := []corev1.Node{
+		node("ssd-a", map[string]string{"disk": "ssd", "zone": "a"}),
+		node("ssd-b", map[string]string{"disk": "ssd", "zone": "b"}),
+		node("hdd-a", map[string]string{"disk": "hdd", "zone": "a"}),
+		node("gpu", map[string]string{"disk": "ssd", "zone": "a"}, corev1.Taint{Key: "gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule}),
+		node("preferred", map[string]string{"disk": "ssd", "zone": "c"}, corev1.Taint{Key: "spot", Effect: corev1.TaintEffectPreferNoSchedule}),
+		cordoned,
+	}
+	affinity := func(terms ...corev1.NodeSelectorTerm) *corev1.Affinity {
+		return &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: terms},
+		}}
+	}
+
+	tests := []struct {
+		name string
+		spec corev1.PodSpec
+		want []string
+	}{
+		{
+			name: "untainted nodes",
+			spec: corev1.PodSpec{},
+			want: []string{"ssd-a", "ssd-b", "hdd-a", "preferred"},
+		},
+		{
+			name: "node selector",
+			spec: corev1.PodSpec{NodeSelector: map[string]string{"disk": "ssd"}},
+			want: []string{"ssd-a", "ssd-b", "preferred"},
+		},
+		{
+			name: "terms of the required node affinity are ORed",
+			spec: corev1.PodSpec{Affinity: affinity(
+				corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{
+					{Key: "disk", Operator: corev1.NodeSelectorOpIn, Values: []string{"ssd"}},
+					{Key: "zone", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"a", "c"}},
+				}},
+				corev1.NodeSelectorTerm{MatchFields: []corev1.NodeSelectorRequirement{
+					{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"hdd-a"}},
+				}},
+			)},
+			want: []string{"ssd-b", "hdd-a"},
+		},
+		{
+			name: "empty term matches no node",
+			spec: corev1.PodSpec{Affinity: affinity(corev1.NodeSelectorTerm{})},
+		},
+		{
+			name: "tolerated taints",
+			spec: corev1.PodSpec{
+				NodeSelector: map[string]string{"zone": "a"},
+				Tolerations:  []corev1.Toleration{{Key: "gpu", Operator: corev1.TolerationOpExists}},
+			},
+			want: []string{"ssd-a", "hdd-a", "gpu"},
+		},
+		{
+			name: "tolerated unschedulable node",
+			spec: corev1.PodSpec{
+				NodeSelector: map[string]string{"disk": "ssd"},
+				Tolerations:  []corev1.Toleration{{Key: corev1.TaintNodeUnschedulable, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}},
+			},
+			want: []string{"ssd-a", "ssd-b", "preferred", "cordoned"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, schedulableNodes(&tt.spec, nodes))
+		})
+	}
+}
+
+func TestCreateImagePullJobs(t *testing.T) {
+	imagePullJobs := func() []*v1alpha1.ImagePullJob {
+		var jobs []*v1alpha1.ImagePullJob
+		for _, image := range []string{"nginx", "envoy", "busybox"} {
+			jobs = append(jobs, &v1alpha1.ImagePullJob{
+				ObjectMeta: v1.ObjectMeta{Name: "web-" + image, Namespace: "default"},
+				Spec:       v1alpha1.ImagePullJobSpec{Image: image},
+			})
+		}
+		return jobs
+	}
+	listJobs := func(kruiseclient *kruisefake.Clientset) int {
+		list, err := kruiseclient.AppsV1alpha1().ImagePullJobs("default").List(context.Background(), v1.ListOptions{})
+		assert.NoError(t, err)
+		return len(list.Items)
+	}
+
+	kruiseclient := kruisefake.NewSimpleClientset()
+	items, err := (&operator{kruiseclientset: kruiseclient}).createImagePullJobs("default", imagePullJobs())
+	assert.NoError(t, err)
+	assert.Len(t, items, 3)
+	assert.Equal(t, 3, listJobs(kruiseclient))
+
+	kruiseclient = kruisefake.NewSimpleClientset()
+	kruiseclient.PrependReactor("create", "imagepulljobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.(k8stesting.CreateAction).GetObject().(*v1alpha1.ImagePullJob).Spec.Image == "busybox" {
+			return true, nil, fmt.Errorf("quota exceeded")
+		}
+		return false, nil, nil
+	})
+	_, err = (&operator{kruiseclientset: kruiseclient}).createImagePullJobs("default", imagePullJobs())
+	assert.EqualError(t, err, "quota exceeded")
+	assert.Equal(t, 0, listJobs(kruiseclient))
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/api"
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/constants"
+	"github.com/duke-git/lancet/v2/slice"
+	appspub "github.com/openkruise/kruise-api/apps/pub"
+	"github.com/openkruise/kruise-api/apps/v1alpha1"
+	"github.com/openkruise/kruise-api/apps/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	LifecycleHookPreDelete     = "PreDelete"
+	LifecycleHookInPlaceUpdate = "InPlaceUpdate"
+)
+
+// LifecyclePod is a pod of a workload with lifecycle hooks and the lifecycle state of the pod.
+type LifecyclePod struct {
+	*corev1.Pod
+	Lifecycle *PodLifecycle `json:"lifecycle,omitempty" description:"lifecycle state of the pod, if the workload has lifecycle hooks"`
+}
+
+// PodLifecycle is the lifecycle state kruise set on a pod and the handlers of the hook the pod waits
+// for, which have to be removed before kruise deletes or updates the pod.
+type PodLifecycle struct {
+	State             string            `json:"state" description:"lifecycle state, e.g. Normal, PreparingDelete or PreparingUpdate"`
+	Timestamp         string            `json:"timestamp,omitempty" description:"time the pod entered the state"`
+	Hook              string            `json:"hook,omitempty" description:"PreDelete or InPlaceUpdate, the hook the pod waits for"`
+	PendingFinalizers []string          `json:"pendingFinalizers,omitempty" description:"finalizers of the hook still on the pod"`
+	PendingLabels     map[string]string `json:"pendingLabels,omitempty" description:"labels of the hook still on the pod"`
+}
+
+// ListWorkloadPods lists the pods of a workload like ListPods, adding the lifecycle state of each pod
+// for workloads with lifecycle hooks.
+func (c *operator) ListWorkloadPods(namespace, resource, name string) (*api.ListResult, error) {
+	pods, err := c.ListPods(namespace, resource, name)
+	if err != nil {
+		return nil, err
+	}
+	if !slice.Contain([]string{constants.CloneSetType, constants.StatefulSetType}, resource) {
+		return pods, nil
+	}
+
+	workload, err := c.resourceGetter.Get(resource, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	lifecycle := workloadLifecycle(workload)
+	if lifecycle == nil {
+		return pods, nil
+	}
+	items := make([]interface{}, 0, len(pods.Items))
+	for _, item := range pods.Items {
+		pod := item.(*corev1.Pod)
+		items = append(items, &LifecyclePod{Pod: pod, Lifecycle: podLifecycle(pod, lifecycle)})
+	}
+	return &api.ListResult{Items: items, TotalItems: pods.TotalItems}, nil
+}
+
+// CompletePodLifecycleHook removes the finalizers and labels of the hook a pod of the workload waits
+// for in the PreparingDelete or PreparingUpdate state, so kruise goes on deleting or updating it.
+func (c *operator) CompletePodLifecycleHook(namespace, resource, name, podName string) (*LifecyclePod, error) {
+	if !slice.Contain([]string{constants.CloneSetType, constants.StatefulSetType}, resource) {
+		return nil, errors.NewBadRequest("resource type is not supported")
+	}
+	workload, err := c.resourceGetter.Get(resource, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	lifecycle := workloadLifecycle(workload)
+	if lifecycle == nil {
+		return nil, errors.NewBadRequest(fmt.Sprintf("%s %s has no lifecycle hooks", resource, name))
+	}
+	pod, err := c.getWorkloadPod(namespace, resource, name, podName)
+	if err != nil {
+		return nil, err
+	}
+
+	state := podLifecycle(pod, lifecycle)
+	if state == nil || state.Hook == "" {
+		return nil, errors.NewBadRequest(fmt.Sprintf("pod %s is not waiting for a lifecycle hook", podName))
+	}
+	if len(state.PendingFinalizers) == 0 && len(state.PendingLabels) == 0 {
+		return &LifecyclePod{Pod: pod, Lifecycle: state}, nil
+	}
+
+	finalizers := make([]string, 0, len(pod.Finalizers))
+	for _, finalizer := range pod.Finalizers {
+		if !slice.Contain(state.PendingFinalizers, finalizer) {
+			finalizers = append(finalizers, finalizer)
+		}
+	}
+	removeLabels := make(map[string]interface{}, len(state.PendingLabels))
+	for key := range state.PendingLabels {
+		removeLabels[key] = nil
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"resourceVersion": pod.ResourceVersion,
+			"finalizers":      finalizers,
+			"labels":          removeLabels,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	patched, err := c.kubernetesclientset.CoreV1().Pods(namespace).Patch(context.Background(), podName, types.MergePatchType, patch, v1.PatchOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return &LifecyclePod{Pod: patched, Lifecycle: podLifecycle(patched, lifecycle)}, nil
+}
+
+func workloadLifecycle(workload runtime.Object) *appspub.Lifecycle {
+	switch workload := workload.(type) {
+	case *v1alpha1.CloneSet:
+		return workload.Spec.Lifecycle
+	case *v1beta1.StatefulSet:
+		return workload.Spec.Lifecycle
+	default:
+		return nil
+	}
+}
+
+// podLifecycle reads the lifecycle state of the pod and, in the PreparingDelete and PreparingUpdate
+// states, the handlers of the hook still on the pod.
+func podLifecycle(pod *corev1.Pod, lifecycle *appspub.Lifecycle) *PodLifecycle {
+	state, ok := pod.Labels[appspub.LifecycleStateKey]
+	if !ok {
+		return nil
+	}
+	podLifecycle := &PodLifecycle{
+		State:     state,
+		Timestamp: pod.Labels[appspub.LifecycleTimestampKey],
+	}
+
+	var hookName string
+	var hook *appspub.LifecycleHook
+	switch appspub.LifecycleStateType(state) {
+	case appspub.LifecycleStatePreparingDelete:
+		hookName, hook = LifecycleHookPreDelete, lifecycle.PreDelete
+	case appspub.LifecycleStatePreparingUpdate:
+		hookName, hook = LifecycleHookInPlaceUpdate, lifecycle.InPlaceUpdate
+	}
+	if hook == nil {
+		return podLifecycle
+	}
+	podLifecycle.Hook = hookName
+
+	for _, finalizer := range hook.FinalizersHandler {
+		if slice.Contain(pod.Finalizers, finalizer) {
+			podLifecycle.PendingFinalizers = append(podLifecycle.PendingFinalizers, finalizer)
+		}
+	}
+	for key, value := range hook.LabelsHandler {
+		if podValue, ok := pod.Labels[key]; ok && podValue == value {
+			if podLifecycle.PendingLabels == nil {
+				podLifecycle.PendingLabels = map[string]string{}
+			}
+			podLifecycle.PendingLabels[key] = value
+		}
+	}
+	return podLifecycle
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"testing"
+
+	appspub "github.com/openkruise/kruise-api/apps/pub"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPodLifecycle(t *testing.T) {
+	lifecycle := &appspub.Lifecycle{
+		PreDelete: &appspub.LifecycleHook{
+			FinalizersHandler: []string{"example.com/unregister"},
+			LabelsHandler:     map[string]string{"example.com/serving": "true"},
+		},
+	}
+	pod := func(state appspub.LifecycleStateType, finalizers []string, labels map[string]string) *corev1.Pod {
+		podLabels := map[string]string{appspub.LifecycleStateKey: string(state)}
+		for key, value := range labels {
+			podLabels[key] = value
+		}
+		return &corev1.Pod{ObjectMeta: v1.ObjectMeta{Name: "web-0", Labels: podLabels, Finalizers: finalizers}}
+	}
+
+	tests := []struct {
+		name string
+		pod  *corev1.Pod
+		want *PodLifecycle
+	}{
+		{
+			name: "no lifecycle state",
+			pod:  &corev1.Pod{},
+		},
+		{
+			name: "normal pod",
+			pod:  pod(appspub.LifecycleStateNormal, []string{"example.com/unregister"}, nil),
+			want: &PodLifecycle{State: "Normal"},
+		},
+		{
+			name: "pod waiting for the pre-delete hook",
+			pod:  pod(appspub.LifecycleStatePreparingDelete, []string{"example.com/unregister", "other"}, map[string]string{"example.com/serving": "true"}),
+			want: &PodLifecycle{
+				State:             "PreparingDelete",
+				Hook:              LifecycleHookPreDelete,
+				PendingFinalizers: []string{"example.com/unregister"},
+				PendingLabels:     map[string]string{"example.com/serving": "true"},
+			},
+		},
+		{
+			name: "completed pre-delete hook",
+			pod:  pod(appspub.LifecycleStatePreparingDelete, nil, map[string]string{"example.com/serving": "false"}),
+			want: &PodLifecycle{State: "PreparingDelete", Hook: LifecycleHookPreDelete},
+		},
+		{
+			name: "preparing update without an in-place update hook",
+			pod:  pod(appspub.LifecycleStatePreparingUpdate, nil, nil),
+			want: &PodLifecycle{State: "PreparingUpdate"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, podLifecycle(tt.pod, lifecycle))
+		})
+	}
+}
+
+func TestLifecyclePodJSON(t *testing.T) {
+	data, err := json.Marshal(&LifecyclePod{
+		Pod:       &corev1.Pod{ObjectMeta: v1.ObjectMeta{Name: "web-0"}},
+		Lifecycle: &PodLifecycle{State: "Normal"},
+	})
+	assert.NoError(t, err)
+
+	fields := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(data, &fields))
+	assert.Equal(t, "web-0", fields["metadata"].(map[string]interface{})["name"])
+	assert.Equal(t, map[string]interface{}{"state": "Normal"}, fields["lifecycle"])
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/constants"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// defaultContainerAnnotation names the container kubectl shows the logs of when none is given.
+const defaultContainerAnnotation = "kubectl.kubernetes.io/default-container"
+
+// StreamPodLogs streams the logs of a container of the pod, the default container if options names
+// none. Following logs ends when ctx is done.
+func (c *operator) StreamPodLogs(ctx context.Context, namespace, podName string, options *corev1.PodLogOptions) (io.ReadCloser, error) {
+	pod, err := c.kubernetesclientset.CoreV1().Pods(namespace).Get(ctx, podName, v1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return c.streamLogs(ctx, pod, options)
+}
+
+// StreamCloneSetLogs streams the logs of all pods of the CloneSet, each line prefixed with the name
+// of its pod. A pod whose logs cannot be read reports the error in its place.
+func (c *operator) StreamCloneSetLogs(ctx context.Context, namespace, name string, options *corev1.PodLogOptions) (io.ReadCloser, error) {
+	cloneSet, err := c.kruiseclientset.AppsV1alpha1().CloneSets(namespace).Get(ctx, name, v1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	pods, err := c.ListPods(namespace, constants.CloneSetType, name)
+	if err != nil {
+		return nil, err
+	}
+
+	streams := make([]podLogStream, 0, len(pods.Items))
+	for _, item := range pods.Items {
+		pod := item.(*corev1.Pod)
+		if !isOwnedBy(pod.ObjectMeta, cloneSet.UID) {
+			continue
+		}
+		streams = append(streams, podLogStream{
+			pod: pod.Name,
+			open: func(ctx context.Context) (io.ReadCloser, error) {
+				return c.streamLogs(ctx, pod, options.DeepCopy())
+			},
+		})
+	}
+	if len(streams) == 0 {
+		return nil, errors.NewBadRequest(fmt.Sprintf("cloneset %s has no pods", name))
+	}
+	return multiplexLogs(ctx, streams), nil
+}
+
+func (c *operator) streamLogs(ctx context.Context, pod *corev1.Pod, options *corev1.PodLogOptions) (io.ReadCloser, error) {
+	if options.Container == "" {
+		options.Container = defaultContainer(pod)
+	}
+	return c.kubernetesclientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, options).Stream(ctx)
+}
+
+// defaultContainer picks the container named by the default container annotation, else the first one.
+func defaultContainer(pod *corev1.Pod) string {
+	if name, ok := pod.Annotations[defaultContainerAnnotation]; ok {
+		for _, container := range pod.Spec.Containers {
+			if container.Name == name {
+				return name
+			}
+		}
+	}
+	if len(pod.Spec.Containers) > 0 {
+		return pod.Spec.Containers[0].Name
+	}
+	return ""
+}
+
+type podLogStream struct {
+	pod  string
+	open func(ctx context.Context) (io.ReadCloser, error)
+}
+
+// multiplexLogs reads the log streams concurrently and merges them line by line, prefixing each line
+// with the pod name. Closing the returned reader stops all streams.
+func multiplexLogs(ctx context.Context, streams []podLogStream) io.ReadCloser {
+	ctx, cancel := context.WithCancel(ctx)
+	reader, writer := io.Pipe()
+
+	var lock sync.Mutex
+	writeLine := func(pod string, line []byte) error {
+		lock.Lock()
+		defer lock.Unlock()
+		if len(line) == 0 || line[len(line)-1] != '\n' {
+			line = append(line, '\n')
+		}
+		_, err := fmt.Fprintf(writer, "[%s] %s", pod, line)
+		return err
+	}
+
+	var wg sync.WaitGroup
+	for _, stream := range streams {
+		wg.Add(1)
+		go func(stream podLogStream) {
+			defer wg.Done()
+			logs, err := stream.open(ctx)
+			if err != nil {
+				_ = writeLine(stream.pod, []byte(fmt.Sprintf("error: %v", err)))
+				return
+			}
+			defer logs.Close()
+
+			lines := bufio.NewReader(logs)
+			for {
+				line, err := lines.ReadBytes('\n')
+				if len(line) > 0 {
+					if writeErr := writeLine(stream.pod, line); writeErr != nil {
+						// the reader was closed
+						return
+					}
+				}
+				if err != nil {
+					if err != io.EOF && ctx.Err() == nil {
+						_ = writeLine(stream.pod, []byte(fmt.Sprintf("error: %v", err)))
+					}
+					return
+				}
+			}
+		}(stream)
+	}
+	go func() {
+		wg.Wait()
+		cancel()
+		_ = writer.Close()
+	}()
+
+	return &multiplexedLogs{PipeReader: reader, cancel: cancel}
+}
+
+type multiplexedLogs struct {
+	*io.PipeReader
+	cancel context.CancelFunc
+}
+
+func (m *multiplexedLogs) Close() error {
+	m.cancel()
+	return m.PipeReader.Close()
+}
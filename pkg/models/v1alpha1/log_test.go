@@ -0,0 +1,104 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMultiplexLogs(t *testing.T) {
+	logStream := func(pod, logs string, err error) podLogStream {
+		return podLogStream{
+			pod: pod,
+			open: func(ctx context.Context) (io.ReadCloser, error) {
+				if err != nil {
+					return nil, err
+				}
+				return io.NopCloser(strings.NewReader(logs)), nil
+			},
+		}
+	}
+
+	logs := multiplexLogs(context.Background(), []podLogStream{
+		logStream("web-a", "starting\nlistening on :80\n", nil),
+		logStream("web-b", "starting\nno trailing newline", nil),
+		logStream("web-c", "", fmt.Errorf("container is waiting to start")),
+	})
+	data, err := io.ReadAll(logs)
+	assert.NoError(t, err)
+	assert.NoError(t, logs.Close())
+
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	// lines of one pod keep their order
+	assert.Less(t, indexOf(lines, "[web-a] starting"), indexOf(lines, "[web-a] listening on :80"))
+	sort.Strings(lines)
+	assert.Equal(t, []string{
+		"[web-a] listening on :80",
+		"[web-a] starting",
+		"[web-b] no trailing newline",
+		"[web-b] starting",
+		"[web-c] error: container is waiting to start",
+	}, lines)
+}
+
+func TestMultiplexLogsClose(t *testing.T) {
+	opened := make(chan struct{})
+	logs := multiplexLogs(context.Background(), []podLogStream{{
+		pod: "web-a",
+		open: func(ctx context.Context) (io.ReadCloser, error) {
+			reader, writer := io.Pipe()
+			go func() {
+				close(opened)
+				<-ctx.Done()
+				_ = writer.CloseWithError(ctx.Err())
+			}()
+			return reader, nil
+		},
+	}})
+	<-opened
+	assert.NoError(t, logs.Close())
+	_, err := io.ReadAll(logs)
+	assert.ErrorIs(t, err, io.ErrClosedPipe)
+}
+
+func TestDefaultContainer(t *testing.T) {
+	pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "main"}, {Name: "sidecar"}}}}
+	assert.Equal(t, "main", defaultContainer(pod))
+	pod.Annotations = map[string]string{defaultContainerAnnotation: "sidecar"}
+	assert.Equal(t, "sidecar", defaultContainer(pod))
+	pod.Annotations = map[string]string{defaultContainerAnnotation: "missing"}
+	assert.Equal(t, "main", defaultContainer(pod))
+	assert.Equal(t, "", defaultContainer(&corev1.Pod{ObjectMeta: v1.ObjectMeta{Name: "empty"}}))
+}
+
+func indexOf(lines []string, line string) int {
+	for i := range lines {
+		if lines[i] == line {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,306 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/constants"
+	"github.com/openkruise/kruise-api/apps/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+)
+
+const (
+	MigrationPending   = "Pending"
+	MigrationMigrating = "Migrating"
+	MigrationCompleted = "Completed"
+	MigrationFailed    = "Failed"
+	MigrationDryRun    = "DryRun"
+
+	defaultMigrationStepReplicas  = 1
+	defaultMigrationTimeoutSecond = 300
+)
+
+// deploymentOnlyAnnotations are maintained by the deployment controller or kubectl and must not be
+// copied to the CloneSet.
+var deploymentOnlyAnnotations = []string{
+	"deployment.kubernetes.io/revision",
+	"kubectl.kubernetes.io/last-applied-configuration",
+	constants.DeploymentMigrationAnnotation,
+}
+
+// MigrateDeployment describes how a Deployment is migrated to a CloneSet.
+type MigrateDeployment struct {
+	CloneSetName string `json:"cloneSetName,omitempty" description:"name of the cloneset, defaults to the name of the deployment"`
+	DryRun       bool   `json:"dryRun,omitempty" description:"only generate the cloneset without creating it"`
+	// ScaleDown creates the CloneSet with no replicas and moves the replicas over step by step,
+	// scaling the Deployment down once the pods added to the CloneSet are ready.
+	ScaleDown      bool  `json:"scaleDown,omitempty" description:"scale the deployment down in step with the cloneset scaling up"`
+	StepReplicas   int32 `json:"stepReplicas,omitempty" description:"replicas moved from the deployment to the cloneset in each step, defaults to 1"`
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty" description:"seconds to wait for the pods of a step to be ready, defaults to 300"`
+}
+
+// DeploymentMigration is the result of migrating a Deployment to a CloneSet.
+type DeploymentMigration struct {
+	CloneSet *v1alpha1.CloneSet        `json:"cloneSet" description:"the generated cloneset"`
+	Status   DeploymentMigrationStatus `json:"status" description:"progress of the migration"`
+}
+
+// DeploymentMigrationStatus is the progress of moving the replicas of a Deployment to a CloneSet, stored in
+// the DeploymentMigrationAnnotation of the Deployment and carried out by the migration controller.
+type DeploymentMigrationStatus struct {
+	Deployment         string   `json:"deployment" description:"name of the deployment"`
+	CloneSet           string   `json:"cloneSet" description:"name of the cloneset"`
+	Phase              string   `json:"phase" description:"Pending, Migrating, Completed, Failed or DryRun"`
+	ScaleDown          bool     `json:"scaleDown,omitempty" description:"whether the replicas are moved from the deployment to the cloneset"`
+	Replicas           int32    `json:"replicas" description:"replicas to move to the cloneset"`
+	CloneSetReplicas   int32    `json:"cloneSetReplicas" description:"replicas of the cloneset"`
+	DeploymentReplicas int32    `json:"deploymentReplicas" description:"replicas left in the deployment"`
+	StepReplicas       int32    `json:"stepReplicas,omitempty" description:"replicas moved in each step"`
+	TimeoutSeconds     int32    `json:"timeoutSeconds,omitempty" description:"seconds to wait for the cloneset to be created and the pods of a step to be ready"`
+	StepStartTime      *v1.Time `json:"stepStartTime,omitempty" description:"time the cloneset was scaled up for the current step"`
+	Message            string   `json:"message,omitempty" description:"reason of the failure"`
+	StartTime          v1.Time  `json:"startTime" description:"time the migration started"`
+	CompletionTime     *v1.Time `json:"completionTime,omitempty" description:"time the migration completed or failed"`
+}
+
+// InProgress reports whether the replicas are still being moved.
+func (s *DeploymentMigrationStatus) InProgress() bool {
+	return s.Phase == MigrationPending || s.Phase == MigrationMigrating
+}
+
+// MigrateDeployment generates a CloneSet equivalent to the Deployment and, unless it is a dry run,
+// creates it. When ScaleDown is set the replicas are moved by the migration controller, and the
+// progress can be followed with GetDeploymentMigration.
+func (c *operator) MigrateDeployment(namespace, name string, migrate *MigrateDeployment) (*DeploymentMigration, error) {
+	deployment, err := c.kubernetesclientset.AppsV1().Deployments(namespace).Get(context.Background(), name, v1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	cloneSetName := migrate.CloneSetName
+	if cloneSetName == "" {
+		cloneSetName = name
+	}
+	cloneSet := cloneSetFromDeployment(deployment, cloneSetName)
+
+	replicas := *cloneSet.Spec.Replicas
+	status := DeploymentMigrationStatus{
+		Deployment:         name,
+		CloneSet:           cloneSetName,
+		Phase:              MigrationPending,
+		ScaleDown:          migrate.ScaleDown,
+		Replicas:           replicas,
+		CloneSetReplicas:   replicas,
+		DeploymentReplicas: replicas,
+		TimeoutSeconds:     migrate.TimeoutSeconds,
+		StartTime:          v1.Now(),
+	}
+	if status.TimeoutSeconds <= 0 {
+		status.TimeoutSeconds = defaultMigrationTimeoutSecond
+	}
+	if migrate.ScaleDown {
+		zero := int32(0)
+		cloneSet.Spec.Replicas = &zero
+		status.CloneSetReplicas = 0
+		status.StepReplicas = migrate.StepReplicas
+		if status.StepReplicas <= 0 {
+			status.StepReplicas = defaultMigrationStepReplicas
+		}
+	}
+	if migrate.DryRun {
+		status.Phase = MigrationDryRun
+		return &DeploymentMigration{CloneSet: cloneSet, Status: status}, nil
+	}
+
+	// the migration is recorded before the CloneSet is created, so the controller picks it up even if
+	// this request fails half way, and a concurrent migration of the same Deployment is rejected
+	var current *DeploymentMigrationStatus
+	err = c.updateDeploymentMigrationStatus(namespace, name, func(existing *DeploymentMigrationStatus) *DeploymentMigrationStatus {
+		if existing != nil && existing.InProgress() {
+			current = existing
+			return nil
+		}
+		return &status
+	})
+	if err != nil {
+		return nil, err
+	}
+	if current != nil {
+		return nil, errors.NewConflict(appsv1.Resource(constants.DeploymentType), name, fmt.Errorf("deployment %s is being migrated to cloneset %s", name, current.CloneSet))
+	}
+
+	created, err := c.kruiseclientset.AppsV1alpha1().CloneSets(namespace).Create(context.Background(), cloneSet, v1.CreateOptions{})
+	if err != nil {
+		completionTime := v1.Now()
+		status.Phase = MigrationFailed
+		status.Message = err.Error()
+		status.CompletionTime = &completionTime
+		if updateErr := c.updateDeploymentMigrationStatus(namespace, name, func(*DeploymentMigrationStatus) *DeploymentMigrationStatus {
+			return &status
+		}); updateErr != nil {
+			klog.Errorf("failed to record the failed migration of deployment %s/%s: %v", namespace, name, updateErr)
+		}
+		return nil, err
+	}
+
+	if !migrate.ScaleDown {
+		// nothing is moved, the Deployment keeps its replicas next to the CloneSet
+		completionTime := v1.Now()
+		status.Phase = MigrationCompleted
+		status.CompletionTime = &completionTime
+		err = c.updateDeploymentMigrationStatus(namespace, name, func(*DeploymentMigrationStatus) *DeploymentMigrationStatus {
+			return &status
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &DeploymentMigration{CloneSet: created, Status: status}, nil
+}
+
+// GetDeploymentMigration returns the progress of the latest migration of the Deployment.
+func (c *operator) GetDeploymentMigration(namespace, name string) (*DeploymentMigrationStatus, error) {
+	deployment, err := c.kubernetesclientset.AppsV1().Deployments(namespace).Get(context.Background(), name, v1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	status, err := GetDeploymentMigrationStatus(deployment)
+	if err != nil {
+		return nil, err
+	}
+	if status == nil {
+		return nil, errors.NewNotFound(appsv1.Resource("deploymentmigrations"), name)
+	}
+	return status, nil
+}
+
+// GetDeploymentMigrationStatus reads the migration status of the Deployment, nil if it was never migrated.
+func GetDeploymentMigrationStatus(deployment *appsv1.Deployment) (*DeploymentMigrationStatus, error) {
+	statusData, ok := deployment.Annotations[constants.DeploymentMigrationAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	status := &DeploymentMigrationStatus{}
+	if err := json.Unmarshal([]byte(statusData), status); err != nil {
+		return nil, fmt.Errorf("invalid migration status of deployment %s: %v", deployment.Name, err)
+	}
+	return status, nil
+}
+
+// updateDeploymentMigrationStatus stores the migration status returned by update on the Deployment, or
+// leaves it when update returns nil. The Deployment is read again on every conflict, its controller
+// changes it all the time.
+func (c *operator) updateDeploymentMigrationStatus(namespace, name string, update func(*DeploymentMigrationStatus) *DeploymentMigrationStatus) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		deployment, err := c.kubernetesclientset.AppsV1().Deployments(namespace).Get(context.Background(), name, v1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		current, err := GetDeploymentMigrationStatus(deployment)
+		if err != nil {
+			return err
+		}
+		status := update(current)
+		if status == nil {
+			return nil
+		}
+		statusData, err := json.Marshal(status)
+		if err != nil {
+			return err
+		}
+		patch, err := json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"resourceVersion": deployment.ResourceVersion,
+				"annotations": map[string]interface{}{
+					constants.DeploymentMigrationAnnotation: string(statusData),
+				},
+			},
+		})
+		if err != nil {
+			return err
+		}
+		_, err = c.kubernetesclientset.AppsV1().Deployments(namespace).Patch(context.Background(), name, types.MergePatchType, patch, v1.PatchOptions{})
+		return err
+	})
+}
+
+// cloneSetFromDeployment generates a CloneSet with the selector, template and update strategy of the Deployment.
+func cloneSetFromDeployment(deployment *appsv1.Deployment, name string) *v1alpha1.CloneSet {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+
+	labels := make(map[string]string)
+	for k, v := range deployment.Labels {
+		labels[k] = v
+	}
+	annotations := make(map[string]string)
+	for k, v := range deployment.Annotations {
+		annotations[k] = v
+	}
+	for _, k := range deploymentOnlyAnnotations {
+		delete(annotations, k)
+	}
+
+	cloneSet := &v1alpha1.CloneSet{
+		TypeMeta: v1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       constants.CloneSetTag,
+		},
+		ObjectMeta: v1.ObjectMeta{
+			Name:        name,
+			Namespace:   deployment.Namespace,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Spec: v1alpha1.CloneSetSpec{
+			Replicas:             &replicas,
+			Selector:             deployment.Spec.Selector.DeepCopy(),
+			Template:             *deployment.Spec.Template.DeepCopy(),
+			RevisionHistoryLimit: deployment.Spec.RevisionHistoryLimit,
+			MinReadySeconds:      deployment.Spec.MinReadySeconds,
+			UpdateStrategy: v1alpha1.CloneSetUpdateStrategy{
+				Type:   v1alpha1.RecreateCloneSetUpdateStrategyType,
+				Paused: deployment.Spec.Paused,
+			},
+		},
+	}
+
+	switch deployment.Spec.Strategy.Type {
+	case appsv1.RecreateDeploymentStrategyType:
+		maxUnavailable := intstr.FromString("100%")
+		maxSurge := intstr.FromInt(0)
+		cloneSet.Spec.UpdateStrategy.MaxUnavailable = &maxUnavailable
+		cloneSet.Spec.UpdateStrategy.MaxSurge = &maxSurge
+	default:
+		if rollingUpdate := deployment.Spec.Strategy.RollingUpdate; rollingUpdate != nil {
+			cloneSet.Spec.UpdateStrategy.MaxUnavailable = rollingUpdate.MaxUnavailable
+			cloneSet.Spec.UpdateStrategy.MaxSurge = rollingUpdate.MaxSurge
+		}
+	}
+	return cloneSet
+}
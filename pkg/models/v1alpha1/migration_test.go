@@ -0,0 +1,203 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/openkruise/kruise-api/apps/v1alpha1"
+	kruisefake "github.com/openkruise/kruise-api/client/clientset/versioned/fake"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestCloneSetFromDeployment(t *testing.T) {
+	replicas := int32(3)
+	maxUnavailable := intstr.FromInt(1)
+	maxSurge := intstr.FromString("25%")
+	deployment := &appsv1.Deployment{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "web",
+			Namespace: "default",
+			Labels:    map[string]string{"app": "web"},
+			Annotations: map[string]string{
+				"deployment.kubernetes.io/revision": "4",
+				"owner":                             "team-a",
+				"workload.kubesphere.io/migration":  `{"phase":"Completed"}`,
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &v1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: v1.ObjectMeta{Labels: map[string]string{"app": "web"}},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "web", Image: "nginx:1.25"}}},
+			},
+			Strategy: appsv1.DeploymentStrategy{
+				Type:          appsv1.RollingUpdateDeploymentStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateDeployment{MaxUnavailable: &maxUnavailable, MaxSurge: &maxSurge},
+			},
+			MinReadySeconds: 5,
+			Paused:          true,
+		},
+	}
+
+	cloneSet := cloneSetFromDeployment(deployment, "web-cloneset")
+	assert.Equal(t, "web-cloneset", cloneSet.Name)
+	assert.Equal(t, "default", cloneSet.Namespace)
+	assert.Equal(t, map[string]string{"owner": "team-a"}, cloneSet.Annotations)
+	assert.Equal(t, int32(3), *cloneSet.Spec.Replicas)
+	assert.Equal(t, deployment.Spec.Selector, cloneSet.Spec.Selector)
+	assert.Equal(t, deployment.Spec.Template, cloneSet.Spec.Template)
+	assert.Equal(t, int32(5), cloneSet.Spec.MinReadySeconds)
+	assert.Equal(t, v1alpha1.RecreateCloneSetUpdateStrategyType, cloneSet.Spec.UpdateStrategy.Type)
+	assert.Equal(t, &maxUnavailable, cloneSet.Spec.UpdateStrategy.MaxUnavailable)
+	assert.Equal(t, &maxSurge, cloneSet.Spec.UpdateStrategy.MaxSurge)
+	assert.True(t, cloneSet.Spec.UpdateStrategy.Paused)
+
+	cloneSet.Labels["migrated"] = "true"
+	assert.Equal(t, map[string]string{"app": "web"}, deployment.Labels)
+
+	deployment.Spec.Strategy = appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType}
+	cloneSet = cloneSetFromDeployment(deployment, "web")
+	assert.Equal(t, intstr.FromString("100%"), *cloneSet.Spec.UpdateStrategy.MaxUnavailable)
+	assert.Equal(t, intstr.FromInt(0), *cloneSet.Spec.UpdateStrategy.MaxSurge)
+}
+
+func TestGetDeploymentMigrationStatus(t *testing.T) {
+	deployment := &appsv1.Deployment{ObjectMeta: v1.ObjectMeta{Name: "web"}}
+	status, err := GetDeploymentMigrationStatus(deployment)
+	assert.NoError(t, err)
+	assert.Nil(t, status)
+
+	deployment.Annotations = map[string]string{"workload.kubesphere.io/migration": `{"cloneSet":"web-cloneset","phase":"Migrating","replicas":3}`}
+	status, err = GetDeploymentMigrationStatus(deployment)
+	assert.NoError(t, err)
+	assert.Equal(t, &DeploymentMigrationStatus{CloneSet: "web-cloneset", Phase: MigrationMigrating, Replicas: 3}, status)
+	assert.True(t, status.InProgress())
+
+	deployment.Annotations["workload.kubesphere.io/migration"] = "{"
+	_, err = GetDeploymentMigrationStatus(deployment)
+	assert.Error(t, err)
+}
+
+func TestMigrateDeployment(t *testing.T) {
+	replicas := int32(3)
+	newDeployment := func() *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: v1.ObjectMeta{Name: "web", Namespace: "default", ResourceVersion: "1"},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: &replicas,
+				Selector: &v1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			},
+		}
+	}
+	// conflicts fails the first patches of the Deployment, like the deployment controller updating its status does
+	conflicts := func(k8sclient *fake.Clientset, times int) {
+		k8sclient.PrependReactor("patch", "deployments", func(k8stesting.Action) (bool, runtime.Object, error) {
+			if times == 0 {
+				return false, nil, nil
+			}
+			times--
+			return true, nil, errors.NewConflict(appsv1.Resource("deployments"), "web", fmt.Errorf("the object has been modified"))
+		})
+	}
+	migrationStatus := func(t *testing.T, c *operator) *DeploymentMigrationStatus {
+		status, err := c.GetDeploymentMigration("default", "web")
+		assert.NoError(t, err)
+		return status
+	}
+
+	t.Run("retries the status on conflicts before creating the cloneset", func(t *testing.T) {
+		k8sclient, kruiseclient := fake.NewSimpleClientset(newDeployment()), kruisefake.NewSimpleClientset()
+		conflicts(k8sclient, 2)
+		c := &operator{kubernetesclientset: k8sclient, kruiseclientset: kruiseclient}
+
+		migration, err := c.MigrateDeployment("default", "web", &MigrateDeployment{ScaleDown: true})
+		assert.NoError(t, err)
+		assert.Equal(t, MigrationPending, migration.Status.Phase)
+		cloneSet, err := kruiseclient.AppsV1alpha1().CloneSets("default").Get(context.Background(), "web", v1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, int32(0), *cloneSet.Spec.Replicas)
+		status := migrationStatus(t, c)
+		assert.Equal(t, MigrationPending, status.Phase)
+		assert.True(t, status.ScaleDown)
+	})
+
+	t.Run("creates no cloneset when the status cannot be recorded", func(t *testing.T) {
+		k8sclient, kruiseclient := fake.NewSimpleClientset(newDeployment()), kruisefake.NewSimpleClientset()
+		conflicts(k8sclient, 100)
+		c := &operator{kubernetesclientset: k8sclient, kruiseclientset: kruiseclient}
+
+		_, err := c.MigrateDeployment("default", "web", &MigrateDeployment{ScaleDown: true})
+		assert.True(t, errors.IsConflict(err))
+		_, err = kruiseclient.AppsV1alpha1().CloneSets("default").Get(context.Background(), "web", v1.GetOptions{})
+		assert.True(t, errors.IsNotFound(err))
+	})
+
+	t.Run("records a failed cloneset creation", func(t *testing.T) {
+		existing := &v1alpha1.CloneSet{ObjectMeta: v1.ObjectMeta{Name: "web", Namespace: "default"}}
+		k8sclient, kruiseclient := fake.NewSimpleClientset(newDeployment()), kruisefake.NewSimpleClientset(existing)
+		c := &operator{kubernetesclientset: k8sclient, kruiseclientset: kruiseclient}
+
+		_, err := c.MigrateDeployment("default", "web", &MigrateDeployment{ScaleDown: true})
+		assert.True(t, errors.IsAlreadyExists(err))
+		status := migrationStatus(t, c)
+		assert.Equal(t, MigrationFailed, status.Phase)
+		assert.NotNil(t, status.CompletionTime)
+
+		// a failed migration does not block the next one
+		_, err = kruiseclient.AppsV1alpha1().CloneSets("default").Get(context.Background(), "web", v1.GetOptions{})
+		assert.NoError(t, err)
+		migration, err := c.MigrateDeployment("default", "web", &MigrateDeployment{CloneSetName: "web-cloneset"})
+		assert.NoError(t, err)
+		assert.Equal(t, MigrationCompleted, migration.Status.Phase)
+		assert.Equal(t, MigrationCompleted, migrationStatus(t, c).Phase)
+	})
+
+	t.Run("rejects a migration in progress", func(t *testing.T) {
+		deployment := newDeployment()
+		deployment.Annotations = map[string]string{"workload.kubesphere.io/migration": `{"cloneSet":"web","phase":"Migrating"}`}
+		k8sclient, kruiseclient := fake.NewSimpleClientset(deployment), kruisefake.NewSimpleClientset()
+		c := &operator{kubernetesclientset: k8sclient, kruiseclientset: kruiseclient}
+
+		_, err := c.MigrateDeployment("default", "web", &MigrateDeployment{CloneSetName: "web-cloneset"})
+		assert.True(t, errors.IsConflict(err))
+		_, err = kruiseclient.AppsV1alpha1().CloneSets("default").Get(context.Background(), "web-cloneset", v1.GetOptions{})
+		assert.True(t, errors.IsNotFound(err))
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		k8sclient, kruiseclient := fake.NewSimpleClientset(newDeployment()), kruisefake.NewSimpleClientset()
+		c := &operator{kubernetesclientset: k8sclient, kruiseclientset: kruiseclient}
+
+		migration, err := c.MigrateDeployment("default", "web", &MigrateDeployment{DryRun: true})
+		assert.NoError(t, err)
+		assert.Equal(t, MigrationDryRun, migration.Status.Phase)
+		_, err = c.GetDeploymentMigration("default", "web")
+		assert.True(t, errors.IsNotFound(err))
+	})
+}
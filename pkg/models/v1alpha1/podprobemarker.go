@@ -0,0 +1,123 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/api"
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/constants"
+	"github.com/openkruise/kruise-api/apps/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PodProbeResult is the probe results of a PodProbeMarker on one of the pods it selects.
+type PodProbeResult struct {
+	Name     string        `json:"name" description:"name of the pod"`
+	NodeName string        `json:"nodeName,omitempty" description:"node the pod is running on"`
+	Probes   []ProbeResult `json:"probes" description:"results of the probes of the podprobemarker"`
+}
+
+// ProbeResult is the latest result of one probe reported in NodePodProbe status.
+type ProbeResult struct {
+	Name               string              `json:"name" description:"name of the probe"`
+	ContainerName      string              `json:"containerName" description:"container the probe runs in"`
+	State              v1alpha1.ProbeState `json:"state" description:"Succeeded, Failed or Unknown"`
+	LastProbeTime      *v1.Time            `json:"lastProbeTime,omitempty" description:"last time the probe was executed"`
+	LastTransitionTime *v1.Time            `json:"lastTransitionTime,omitempty" description:"last time the probe state changed"`
+	Message            string              `json:"message,omitempty" description:"message of the last probe"`
+}
+
+// ListPodProbeResults lists the pods selected by the PodProbeMarker together with the results
+// of its probes, as reported by the NodePodProbe of the node each pod runs on.
+func (c *operator) ListPodProbeResults(namespace, name string) (*api.ListResult, error) {
+	obj, err := c.resourceGetter.Get(constants.PodProbeMarkerType, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	podProbeMarker := obj.(*v1alpha1.PodProbeMarker)
+
+	pods, err := c.ListPods(namespace, constants.PodProbeMarkerType, name)
+	if err != nil {
+		return nil, err
+	}
+
+	nodePodProbes := make(map[string]*v1alpha1.NodePodProbe)
+	items := make([]interface{}, 0, len(pods.Items))
+	for _, item := range pods.Items {
+		pod := item.(*corev1.Pod)
+
+		var nodePodProbe *v1alpha1.NodePodProbe
+		if nodeName := pod.Spec.NodeName; nodeName != "" {
+			if cached, ok := nodePodProbes[nodeName]; ok {
+				nodePodProbe = cached
+			} else {
+				obj, err := c.resourceGetter.Get(constants.NodePodProbeType, "", nodeName)
+				if err != nil && !errors.IsNotFound(err) {
+					return nil, err
+				}
+				if err == nil {
+					nodePodProbe = obj.(*v1alpha1.NodePodProbe)
+				}
+				nodePodProbes[nodeName] = nodePodProbe
+			}
+		}
+
+		items = append(items, podProbeResult(podProbeMarker, pod, nodePodProbe))
+	}
+	return api.NewListResult(items, len(items)), nil
+}
+
+// podProbeResult joins the probes of the PodProbeMarker with the states reported for the pod,
+// probes without a reported state are Unknown.
+func podProbeResult(podProbeMarker *v1alpha1.PodProbeMarker, pod *corev1.Pod, nodePodProbe *v1alpha1.NodePodProbe) PodProbeResult {
+	states := make(map[string]v1alpha1.ContainerProbeState)
+	if nodePodProbe != nil {
+		for _, status := range nodePodProbe.Status.PodProbeStatuses {
+			if status.Namespace != pod.Namespace || status.Name != pod.Name || status.UID != string(pod.UID) {
+				continue
+			}
+			for _, state := range status.ProbeStates {
+				states[state.Name] = state
+			}
+		}
+	}
+
+	result := PodProbeResult{
+		Name:     pod.Name,
+		NodeName: pod.Spec.NodeName,
+		Probes:   make([]ProbeResult, 0, len(podProbeMarker.Spec.Probes)),
+	}
+	for _, probe := range podProbeMarker.Spec.Probes {
+		probeResult := ProbeResult{
+			Name:          probe.Name,
+			ContainerName: probe.ContainerName,
+			State:         v1alpha1.ProbeUnknown,
+		}
+		// NodePodProbe names the probes of a PodProbeMarker as <podprobemarker>#<probe>
+		if state, ok := states[fmt.Sprintf("%s#%s", podProbeMarker.Name, probe.Name)]; ok {
+			probeResult.State = state.State
+			probeResult.LastProbeTime = &state.LastProbeTime
+			probeResult.LastTransitionTime = &state.LastTransitionTime
+			probeResult.Message = state.Message
+		}
+		result.Probes = append(result.Probes, probeResult)
+	}
+	return result
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openkruise/kruise-api/apps/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPodProbeResult(t *testing.T) {
+	probed := v1.NewTime(time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC))
+	transitioned := v1.NewTime(time.Date(2023, 6, 1, 9, 0, 0, 0, time.UTC))
+
+	podProbeMarker := &v1alpha1.PodProbeMarker{
+		ObjectMeta: v1.ObjectMeta{Name: "health", Namespace: "default"},
+		Spec: v1alpha1.PodProbeMarkerSpec{
+			Probes: []v1alpha1.PodContainerProbe{
+				{Name: "alive", ContainerName: "main"},
+				{Name: "ready", ContainerName: "sidecar"},
+			},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{Name: "web-1", Namespace: "default", UID: "uid-1"},
+		Spec:       corev1.PodSpec{NodeName: "node-1"},
+	}
+	state := func(name string, probeState v1alpha1.ProbeState, message string) v1alpha1.ContainerProbeState {
+		return v1alpha1.ContainerProbeState{
+			Name:               name,
+			State:              probeState,
+			LastProbeTime:      probed,
+			LastTransitionTime: transitioned,
+			Message:            message,
+		}
+	}
+	nodePodProbe := func(statuses ...v1alpha1.PodProbeStatus) *v1alpha1.NodePodProbe {
+		return &v1alpha1.NodePodProbe{
+			ObjectMeta: v1.ObjectMeta{Name: "node-1"},
+			Status:     v1alpha1.NodePodProbeStatus{PodProbeStatuses: statuses},
+		}
+	}
+	unknown := func(name, containerName string) ProbeResult {
+		return ProbeResult{Name: name, ContainerName: containerName, State: v1alpha1.ProbeUnknown}
+	}
+
+	tests := []struct {
+		name         string
+		nodePodProbe *v1alpha1.NodePodProbe
+		want         []ProbeResult
+	}{
+		{
+			name: "probes of several markers on the pod",
+			nodePodProbe: nodePodProbe(v1alpha1.PodProbeStatus{
+				Namespace: "default",
+				Name:      "web-1",
+				UID:       "uid-1",
+				ProbeStates: []v1alpha1.ContainerProbeState{
+					state("health#alive", v1alpha1.ProbeSucceeded, ""),
+					state("other#alive", v1alpha1.ProbeFailed, "other marker"),
+					state("health#ready", v1alpha1.ProbeFailed, "connection refused"),
+					state("other#ready", v1alpha1.ProbeSucceeded, ""),
+				},
+			}),
+			want: []ProbeResult{
+				{Name: "alive", ContainerName: "main", State: v1alpha1.ProbeSucceeded, LastProbeTime: &probed, LastTransitionTime: &transitioned},
+				{Name: "ready", ContainerName: "sidecar", State: v1alpha1.ProbeFailed, LastProbeTime: &probed, LastTransitionTime: &transitioned, Message: "connection refused"},
+			},
+		},
+		{
+			name: "only one probe reported yet",
+			nodePodProbe: nodePodProbe(v1alpha1.PodProbeStatus{
+				Namespace:   "default",
+				Name:        "web-1",
+				UID:         "uid-1",
+				ProbeStates: []v1alpha1.ContainerProbeState{state("health#alive", v1alpha1.ProbeSucceeded, "")},
+			}),
+			want: []ProbeResult{
+				{Name: "alive", ContainerName: "main", State: v1alpha1.ProbeSucceeded, LastProbeTime: &probed, LastTransitionTime: &transitioned},
+				unknown("ready", "sidecar"),
+			},
+		},
+		{
+			name: "pod missing from the node pod probe",
+			nodePodProbe: nodePodProbe(v1alpha1.PodProbeStatus{
+				Namespace:   "default",
+				Name:        "web-2",
+				UID:         "uid-2",
+				ProbeStates: []v1alpha1.ContainerProbeState{state("health#alive", v1alpha1.ProbeSucceeded, "")},
+			}),
+			want: []ProbeResult{unknown("alive", "main"), unknown("ready", "sidecar")},
+		},
+		{
+			name: "status of a previous pod with the same name",
+			nodePodProbe: nodePodProbe(v1alpha1.PodProbeStatus{
+				Namespace:   "default",
+				Name:        "web-1",
+				UID:         "uid-0",
+				ProbeStates: []v1alpha1.ContainerProbeState{state("health#alive", v1alpha1.ProbeFailed, "")},
+			}),
+			want: []ProbeResult{unknown("alive", "main"), unknown("ready", "sidecar")},
+		},
+		{
+			name: "pod with the same name in another namespace",
+			nodePodProbe: nodePodProbe(v1alpha1.PodProbeStatus{
+				Namespace:   "staging",
+				Name:        "web-1",
+				UID:         "uid-1",
+				ProbeStates: []v1alpha1.ContainerProbeState{state("health#alive", v1alpha1.ProbeFailed, "")},
+			}),
+			want: []ProbeResult{unknown("alive", "main"), unknown("ready", "sidecar")},
+		},
+		{
+			name: "no node pod probe for the node",
+			want: []ProbeResult{unknown("alive", "main"), unknown("ready", "sidecar")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := podProbeResult(podProbeMarker, pod, tt.nodePodProbe)
+			assert.Equal(t, "web-1", result.Name)
+			assert.Equal(t, "node-1", result.NodeName)
+			assert.Equal(t, tt.want, result.Probes)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/constants"
+	"github.com/openkruise/kruise-api/apps/v1alpha1"
+	kruisepolicyv1alpha1 "github.com/openkruise/kruise-api/policy/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// WorkloadPodUnavailableBudget reports the PodUnavailableBudget covering a workload.
+type WorkloadPodUnavailableBudget struct {
+	PodUnavailableBudget string `json:"podUnavailableBudget" description:"name of the podunavailablebudget covering the workload"`
+	UnavailableAllowed   int32  `json:"unavailableAllowed" description:"number of pod disruptions that are currently allowed"`
+	CurrentAvailable     int32  `json:"currentAvailable" description:"current number of available pods"`
+	DesiredAvailable     int32  `json:"desiredAvailable" description:"minimum desired number of available pods"`
+	TotalReplicas        int32  `json:"totalReplicas" description:"total number of pods counted by the podunavailablebudget"`
+}
+
+// GetCloneSetPodUnavailableBudget finds the PodUnavailableBudget covering the CloneSet, either
+// through its targetRef or through a selector matching the CloneSet pod template.
+func (c *operator) GetCloneSetPodUnavailableBudget(namespace, name string) (*WorkloadPodUnavailableBudget, error) {
+	obj, err := c.resourceGetter.Get(constants.CloneSetType, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	cloneSet := obj.(*v1alpha1.CloneSet)
+
+	list, err := c.kruiseclientset.PolicyV1alpha1().PodUnavailableBudgets(namespace).List(context.Background(), v1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pub := range list.Items {
+		if !podUnavailableBudgetCovers(&pub, constants.CloneSetTag, cloneSet.Name, cloneSet.Spec.Template.Labels) {
+			continue
+		}
+		return &WorkloadPodUnavailableBudget{
+			PodUnavailableBudget: pub.Name,
+			UnavailableAllowed:   pub.Status.UnavailableAllowed,
+			CurrentAvailable:     pub.Status.CurrentAvailable,
+			DesiredAvailable:     pub.Status.DesiredAvailable,
+			TotalReplicas:        pub.Status.TotalReplicas,
+		}, nil
+	}
+	return nil, errors.NewNotFound(kruisepolicyv1alpha1.Resource(constants.PodUnavailableBudgetType), name)
+}
+
+func podUnavailableBudgetCovers(pub *kruisepolicyv1alpha1.PodUnavailableBudget, kind, name string, podLabels map[string]string) bool {
+	if target := pub.Spec.TargetReference; target != nil {
+		return target.Kind == kind && target.Name == name
+	}
+	if pub.Spec.Selector == nil {
+		return false
+	}
+	selector, err := v1.LabelSelectorAsSelector(pub.Spec.Selector)
+	if err != nil || selector.Empty() {
+		return false
+	}
+	return selector.Matches(labels.Set(podLabels))
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/constants"
+	kruisepolicyv1alpha1 "github.com/openkruise/kruise-api/policy/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPodUnavailableBudgetCovers(t *testing.T) {
+	podLabels := map[string]string{"app": "web", "tier": "frontend"}
+	tests := []struct {
+		name string
+		spec kruisepolicyv1alpha1.PodUnavailableBudgetSpec
+		want bool
+	}{
+		{
+			name: "target reference to the cloneset",
+			spec: kruisepolicyv1alpha1.PodUnavailableBudgetSpec{
+				TargetReference: &kruisepolicyv1alpha1.TargetReference{APIVersion: "apps.kruise.io/v1alpha1", Kind: constants.CloneSetTag, Name: "web"},
+			},
+			want: true,
+		},
+		{
+			name: "target reference to another cloneset",
+			spec: kruisepolicyv1alpha1.PodUnavailableBudgetSpec{
+				TargetReference: &kruisepolicyv1alpha1.TargetReference{APIVersion: "apps.kruise.io/v1alpha1", Kind: constants.CloneSetTag, Name: "api"},
+			},
+			want: false,
+		},
+		{
+			name: "target reference to a deployment with the same name",
+			spec: kruisepolicyv1alpha1.PodUnavailableBudgetSpec{
+				TargetReference: &kruisepolicyv1alpha1.TargetReference{APIVersion: "apps/v1", Kind: constants.DeploymentTag, Name: "web"},
+			},
+			want: false,
+		},
+		{
+			name: "target reference wins over a matching selector",
+			spec: kruisepolicyv1alpha1.PodUnavailableBudgetSpec{
+				TargetReference: &kruisepolicyv1alpha1.TargetReference{APIVersion: "apps.kruise.io/v1alpha1", Kind: constants.CloneSetTag, Name: "api"},
+				Selector:        &v1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			},
+			want: false,
+		},
+		{
+			name: "selector matching the pod template",
+			spec: kruisepolicyv1alpha1.PodUnavailableBudgetSpec{
+				Selector: &v1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			},
+			want: true,
+		},
+		{
+			name: "selector expression matching the pod template",
+			spec: kruisepolicyv1alpha1.PodUnavailableBudgetSpec{
+				Selector: &v1.LabelSelector{MatchExpressions: []v1.LabelSelectorRequirement{
+					{Key: "tier", Operator: v1.LabelSelectorOpIn, Values: []string{"frontend", "backend"}},
+				}},
+			},
+			want: true,
+		},
+		{
+			name: "selector not matching the pod template",
+			spec: kruisepolicyv1alpha1.PodUnavailableBudgetSpec{
+				Selector: &v1.LabelSelector{MatchLabels: map[string]string{"app": "web", "tier": "backend"}},
+			},
+			want: false,
+		},
+		{
+			name: "empty selector covers nothing",
+			spec: kruisepolicyv1alpha1.PodUnavailableBudgetSpec{Selector: &v1.LabelSelector{}},
+			want: false,
+		},
+		{
+			name: "invalid selector covers nothing",
+			spec: kruisepolicyv1alpha1.PodUnavailableBudgetSpec{
+				Selector: &v1.LabelSelector{MatchExpressions: []v1.LabelSelectorRequirement{{Key: "app", Operator: "Like"}}},
+			},
+			want: false,
+		},
+		{
+			name: "neither target reference nor selector",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pub := &kruisepolicyv1alpha1.PodUnavailableBudget{Spec: tt.spec}
+			assert.Equal(t, tt.want, podUnavailableBudgetCovers(pub, constants.CloneSetTag, "web", podLabels))
+		})
+	}
+}
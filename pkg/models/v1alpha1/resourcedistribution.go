@@ -0,0 +1,137 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/api"
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/constants"
+	"github.com/duke-git/lancet/v2/slice"
+	"github.com/openkruise/kruise-api/apps/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	distributionSucceeded = "Succeeded"
+	distributionFailed    = "Failed"
+	distributionPending   = "Pending"
+)
+
+// forbiddenNamespaces are never distributed to by kruise.
+var forbiddenNamespaces = []string{"kube-system", "kube-public"}
+
+// DistributionTarget is the distribution result of a ResourceDistribution in one target namespace.
+type DistributionTarget struct {
+	Namespace string `json:"namespace" description:"target namespace"`
+	Status    string `json:"status" description:"Succeeded, Failed or Pending until kruise observed the latest spec"`
+	Reason    string `json:"reason,omitempty" description:"type of the condition reporting the failure"`
+	Message   string `json:"message,omitempty" description:"reason of the failure"`
+}
+
+// ListResourceDistributionTargets lists every namespace targeted by the ResourceDistribution
+// and whether the resource was distributed to it.
+func (c *operator) ListResourceDistributionTargets(name string) (*api.ListResult, error) {
+	obj, err := c.resourceGetter.Get(constants.ResourceDistributionType, "", name)
+	if err != nil {
+		return nil, err
+	}
+	resourceDistribution := obj.(*v1alpha1.ResourceDistribution)
+
+	namespaces, err := c.kubernetesclientset.CoreV1().Namespaces().List(context.Background(), v1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	targets, err := distributionTargets(resourceDistribution, namespaces.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]interface{}, 0, len(targets))
+	for _, target := range targets {
+		items = append(items, target)
+	}
+	return api.NewListResult(items, len(items)), nil
+}
+
+// distributionTargets resolves the target namespaces of the ResourceDistribution and marks the ones
+// reported in its failure conditions, sorted by namespace. Until kruise observed the latest spec the
+// conditions are stale, so every target is pending.
+func distributionTargets(resourceDistribution *v1alpha1.ResourceDistribution, namespaces []corev1.Namespace) ([]DistributionTarget, error) {
+	spec := resourceDistribution.Spec.Targets
+
+	excluded := make(map[string]bool)
+	for _, namespace := range spec.ExcludedNamespaces.List {
+		excluded[namespace.Name] = true
+	}
+	included := make(map[string]bool)
+	for _, namespace := range spec.IncludedNamespaces.List {
+		included[namespace.Name] = true
+	}
+
+	var selector labels.Selector
+	if len(spec.NamespaceLabelSelector.MatchLabels) > 0 || len(spec.NamespaceLabelSelector.MatchExpressions) > 0 {
+		var err error
+		selector, err = v1.LabelSelectorAsSelector(&spec.NamespaceLabelSelector)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	failed := make(map[string]v1alpha1.ResourceDistributionCondition)
+	for _, condition := range resourceDistribution.Status.Conditions {
+		for _, namespace := range condition.FailedNamespaces {
+			failed[namespace] = condition
+		}
+	}
+
+	targeted := make(map[string]bool)
+	for _, namespace := range namespaces {
+		if excluded[namespace.Name] || slice.Contain(forbiddenNamespaces, namespace.Name) {
+			continue
+		}
+		if spec.AllNamespaces || included[namespace.Name] || (selector != nil && selector.Matches(labels.Set(namespace.Labels))) {
+			targeted[namespace.Name] = true
+		}
+	}
+	// included namespaces that do not exist are reported through the NamespaceNotExists condition
+	for namespace := range failed {
+		targeted[namespace] = true
+	}
+
+	observed := resourceDistribution.Status.ObservedGeneration >= resourceDistribution.Generation
+	targets := make([]DistributionTarget, 0, len(targeted))
+	for namespace := range targeted {
+		target := DistributionTarget{Namespace: namespace, Status: distributionSucceeded}
+		if !observed {
+			target.Status = distributionPending
+		} else if condition, ok := failed[namespace]; ok {
+			target.Status = distributionFailed
+			target.Reason = string(condition.Type)
+			target.Message = condition.Reason
+		}
+		targets = append(targets, target)
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].Namespace < targets[j].Namespace
+	})
+	return targets, nil
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/openkruise/kruise-api/apps/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDistributionTargets(t *testing.T) {
+	namespaces := []corev1.Namespace{
+		{ObjectMeta: v1.ObjectMeta{Name: "default"}},
+		{ObjectMeta: v1.ObjectMeta{Name: "tenant-a", Labels: map[string]string{"tenant": "true"}}},
+		{ObjectMeta: v1.ObjectMeta{Name: "tenant-b", Labels: map[string]string{"tenant": "true"}}},
+		{ObjectMeta: v1.ObjectMeta{Name: "kube-system"}},
+	}
+
+	resourceDistribution := &v1alpha1.ResourceDistribution{
+		Spec: v1alpha1.ResourceDistributionSpec{
+			Targets: v1alpha1.ResourceDistributionTargets{
+				IncludedNamespaces: v1alpha1.ResourceDistributionTargetNamespaces{
+					List: []v1alpha1.ResourceDistributionNamespace{{Name: "default"}},
+				},
+				ExcludedNamespaces: v1alpha1.ResourceDistributionTargetNamespaces{
+					List: []v1alpha1.ResourceDistributionNamespace{{Name: "tenant-b"}},
+				},
+				NamespaceLabelSelector: v1.LabelSelector{MatchLabels: map[string]string{"tenant": "true"}},
+			},
+		},
+		Status: v1alpha1.ResourceDistributionStatus{
+			Conditions: []v1alpha1.ResourceDistributionCondition{
+				{
+					Type:             v1alpha1.ResourceDistributionConflictOccurred,
+					Reason:           "secret already exists",
+					FailedNamespaces: []string{"tenant-a"},
+				},
+				{
+					Type:             v1alpha1.ResourceDistributionNamespaceNotExists,
+					Reason:           "namespace not found",
+					FailedNamespaces: []string{"missing"},
+				},
+			},
+		},
+	}
+
+	targets, err := distributionTargets(resourceDistribution, namespaces)
+	assert.NoError(t, err)
+	assert.Equal(t, []DistributionTarget{
+		{Namespace: "default", Status: distributionSucceeded},
+		{Namespace: "missing", Status: distributionFailed, Reason: "NamespaceNotExists", Message: "namespace not found"},
+		{Namespace: "tenant-a", Status: distributionFailed, Reason: "ConflictOccurred", Message: "secret already exists"},
+	}, targets)
+}
+
+func TestDistributionTargetsAllNamespaces(t *testing.T) {
+	namespaces := []corev1.Namespace{
+		{ObjectMeta: v1.ObjectMeta{Name: "default"}},
+		{ObjectMeta: v1.ObjectMeta{Name: "kube-public"}},
+		{ObjectMeta: v1.ObjectMeta{Name: "kube-system"}},
+		{ObjectMeta: v1.ObjectMeta{Name: "tenant-a"}},
+	}
+	resourceDistribution := &v1alpha1.ResourceDistribution{
+		ObjectMeta: v1.ObjectMeta{Generation: 2},
+		Spec: v1alpha1.ResourceDistributionSpec{
+			Targets: v1alpha1.ResourceDistributionTargets{AllNamespaces: true},
+		},
+		Status: v1alpha1.ResourceDistributionStatus{
+			ObservedGeneration: 1,
+			Conditions: []v1alpha1.ResourceDistributionCondition{
+				{Type: v1alpha1.ResourceDistributionConflictOccurred, FailedNamespaces: []string{"tenant-a"}},
+			},
+		},
+	}
+
+	targets, err := distributionTargets(resourceDistribution, namespaces)
+	assert.NoError(t, err)
+	assert.Equal(t, []DistributionTarget{
+		{Namespace: "default", Status: distributionPending},
+		{Namespace: "tenant-a", Status: distributionPending},
+	}, targets)
+
+	resourceDistribution.Status.ObservedGeneration = 2
+	targets, err = distributionTargets(resourceDistribution, namespaces)
+	assert.NoError(t, err)
+	assert.Equal(t, []DistributionTarget{
+		{Namespace: "default", Status: distributionSucceeded},
+		{Namespace: "tenant-a", Status: distributionFailed, Reason: "ConflictOccurred"},
+	}, targets)
+}
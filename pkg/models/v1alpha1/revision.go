@@ -0,0 +1,273 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/openkruise/kruise-api/apps/v1alpha1"
+	kruiseclientset "github.com/openkruise/kruise-api/client/clientset/versioned"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+// CloneSetRevision summarizes a ControllerRevision of a CloneSet.
+type CloneSetRevision struct {
+	Name              string           `json:"name" description:"name of the controller revision"`
+	Revision          int64            `json:"revision" description:"revision number"`
+	CreationTimestamp v1.Time          `json:"creationTimestamp" description:"creation time of the revision"`
+	Images            []ContainerImage `json:"images" description:"images of the containers in the pod template"`
+	Current           bool             `json:"current,omitempty" description:"whether this is the current revision of the cloneset"`
+	Update            bool             `json:"update,omitempty" description:"whether this is the update revision of the cloneset"`
+}
+
+// ContainerImage is the image of a container in a pod template.
+type ContainerImage struct {
+	Container string `json:"container" description:"name of the container"`
+	Image     string `json:"image" description:"image of the container"`
+	Init      bool   `json:"init,omitempty" description:"whether the container is an init container"`
+}
+
+// CloneSetRollback chooses the revision to restore the template of a CloneSet from.
+type CloneSetRollback struct {
+	// Revision is the revision number to roll back to, 0 rolls back to the previous revision.
+	Revision int64 `json:"revision,omitempty" description:"revision number to roll back to, 0 or omitted for the previous revision"`
+}
+
+// ListCloneSetRevisionHistory lists the revisions of the CloneSet, newest first.
+func (c *operator) ListCloneSetRevisionHistory(namespace, name string) ([]CloneSetRevision, error) {
+	cloneSet, err := c.kruiseclientset.AppsV1alpha1().CloneSets(namespace).Get(context.Background(), name, v1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	revisions, err := ListCloneSetRevisions(context.Background(), c.kubernetesclientset, cloneSet)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]CloneSetRevision, 0, len(revisions))
+	for i := len(revisions) - 1; i >= 0; i-- {
+		revision := revisions[i]
+		item := CloneSetRevision{
+			Name:              revision.Name,
+			Revision:          revision.Revision,
+			CreationTimestamp: revision.CreationTimestamp,
+			Current:           revision.Name == cloneSet.Status.CurrentRevision,
+			Update:            revision.Name == cloneSet.Status.UpdateRevision,
+		}
+		template, err := TemplateFromRevision(revision)
+		if err != nil {
+			return nil, err
+		}
+		item.Images = containerImages(template)
+		history = append(history, item)
+	}
+	return history, nil
+}
+
+// RollbackCloneSetToRevision restores the pod template of the CloneSet from one of its revisions. The
+// update strategy is left alone, so a partition still holds back the pods below it.
+func (c *operator) RollbackCloneSetToRevision(namespace, name string, rollback *CloneSetRollback) (*v1alpha1.CloneSet, error) {
+	if rollback.Revision < 0 {
+		return nil, errors.NewBadRequest("revision must not be negative")
+	}
+
+	var rolledBack *v1alpha1.CloneSet
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cloneSet, err := c.kruiseclientset.AppsV1alpha1().CloneSets(namespace).Get(context.Background(), name, v1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		revisions, err := ListCloneSetRevisions(context.Background(), c.kubernetesclientset, cloneSet)
+		if err != nil {
+			return err
+		}
+
+		var target *appsv1.ControllerRevision
+		if rollback.Revision == 0 {
+			target = PreviousRevision(cloneSet, revisions)
+			if target == nil {
+				return errors.NewBadRequest(fmt.Sprintf("cloneset %s has no previous revision", name))
+			}
+		} else {
+			for _, revision := range revisions {
+				if revision.Revision == rollback.Revision {
+					target = revision
+					break
+				}
+			}
+			if target == nil {
+				return errors.NewNotFound(appsv1.Resource("controllerrevisions"), fmt.Sprintf("%s revision %d", name, rollback.Revision))
+			}
+		}
+
+		if target.Name == cloneSet.Status.UpdateRevision {
+			// the template already matches the revision
+			rolledBack = cloneSet
+			return nil
+		}
+		rolledBack, err = RollbackCloneSet(context.Background(), c.kruiseclientset, cloneSet, target, nil)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rolledBack, nil
+}
+
+func containerImages(template *corev1.PodTemplateSpec) []ContainerImage {
+	images := make([]ContainerImage, 0, len(template.Spec.InitContainers)+len(template.Spec.Containers))
+	for _, container := range template.Spec.InitContainers {
+		images = append(images, ContainerImage{Container: container.Name, Image: container.Image, Init: true})
+	}
+	for _, container := range template.Spec.Containers {
+		images = append(images, ContainerImage{Container: container.Name, Image: container.Image})
+	}
+	return images
+}
+
+// ListCloneSetRevisions lists the ControllerRevisions owned by the CloneSet, oldest revision first.
+func ListCloneSetRevisions(ctx context.Context, clientset kubernetes.Interface, cloneSet *v1alpha1.CloneSet) ([]*appsv1.ControllerRevision, error) {
+	matchLabels, err := v1.LabelSelectorAsMap(cloneSet.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	revisionList, err := clientset.AppsV1().ControllerRevisions(cloneSet.Namespace).List(ctx, v1.ListOptions{LabelSelector: labels.Set(matchLabels).String()})
+	if err != nil {
+		return nil, err
+	}
+
+	return ownedRevisions(revisionList.Items, cloneSet.UID), nil
+}
+
+// sidecarSetRevisionLabel is set by kruise on the ControllerRevisions of a SidecarSet, which live in
+// the namespace of kruise.
+const sidecarSetRevisionLabel = "kruise.io/sidecarset-name"
+
+// ListSidecarSetRevisions lists the ControllerRevisions owned by the SidecarSet, oldest revision first.
+func ListSidecarSetRevisions(ctx context.Context, clientset kubernetes.Interface, sidecarSet *v1alpha1.SidecarSet) ([]*appsv1.ControllerRevision, error) {
+	selector := labels.Set{sidecarSetRevisionLabel: sidecarSet.Name}.String()
+	revisionList, err := clientset.AppsV1().ControllerRevisions(v1.NamespaceAll).List(ctx, v1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+	return ownedRevisions(revisionList.Items, sidecarSet.UID), nil
+}
+
+// PreviousRevision returns the revision the CloneSet ran before its update revision: the current
+// revision while a rollout is in progress, otherwise the newest other revision. It returns nil if
+// there is none.
+func PreviousRevision(cloneSet *v1alpha1.CloneSet, revisions []*appsv1.ControllerRevision) *appsv1.ControllerRevision {
+	updateRevision := cloneSet.Status.UpdateRevision
+	if currentRevision := cloneSet.Status.CurrentRevision; currentRevision != updateRevision {
+		for _, revision := range revisions {
+			if revision.Name == currentRevision {
+				return revision
+			}
+		}
+	}
+	for i := len(revisions) - 1; i >= 0; i-- {
+		if revisions[i].Name != updateRevision {
+			return revisions[i]
+		}
+	}
+	return nil
+}
+
+// TemplateFromRevision decodes the pod template saved in a CloneSet ControllerRevision, which kruise
+// stores as a {"spec":{"template":{...}}} patch.
+func TemplateFromRevision(revision *appsv1.ControllerRevision) (*corev1.PodTemplateSpec, error) {
+	var data struct {
+		Spec struct {
+			Template map[string]interface{} `json:"template"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(revision.Data.Raw, &data); err != nil {
+		return nil, fmt.Errorf("invalid data of revision %s: %v", revision.Name, err)
+	}
+	if data.Spec.Template == nil {
+		return nil, fmt.Errorf("revision %s has no pod template", revision.Name)
+	}
+	delete(data.Spec.Template, "$patch")
+
+	raw, err := json.Marshal(data.Spec.Template)
+	if err != nil {
+		return nil, err
+	}
+	template := &corev1.PodTemplateSpec{}
+	if err = json.Unmarshal(raw, template); err != nil {
+		return nil, fmt.Errorf("invalid pod template of revision %s: %v", revision.Name, err)
+	}
+	return template, nil
+}
+
+// RollbackCloneSet restores the pod template of the CloneSet from the revision, mutate can change
+// the CloneSet further before it is updated.
+func RollbackCloneSet(ctx context.Context, clientset kruiseclientset.Interface, cloneSet *v1alpha1.CloneSet, revision *appsv1.ControllerRevision, mutate func(cloneSet *v1alpha1.CloneSet)) (*v1alpha1.CloneSet, error) {
+	template, err := TemplateFromRevision(revision)
+	if err != nil {
+		return nil, err
+	}
+
+	rolledBack := cloneSet.DeepCopy()
+	rolledBack.Spec.Template = *template
+	if mutate != nil {
+		mutate(rolledBack)
+	}
+	return clientset.AppsV1alpha1().CloneSets(cloneSet.Namespace).Update(ctx, rolledBack, v1.UpdateOptions{})
+}
+
+func ownedRevisions(items []appsv1.ControllerRevision, owner types.UID) []*appsv1.ControllerRevision {
+	revisions := make([]*appsv1.ControllerRevision, 0, len(items))
+	for i := range items {
+		if isOwnedBy(items[i].ObjectMeta, owner) {
+			revisions = append(revisions, &items[i])
+		}
+	}
+	sort.SliceStable(revisions, func(i, j int) bool {
+		return revisions[i].Revision < revisions[j].Revision
+	})
+	return revisions
+}
+
+// IsPodAtRevision reports whether the pod was created from the revision, the revision label of a
+// pod may only carry the hash suffix of the revision name.
+func IsPodAtRevision(pod *corev1.Pod, revision string) bool {
+	podRevision := pod.Labels[appsv1.ControllerRevisionHashLabelKey]
+	if podRevision == "" || revision == "" {
+		return false
+	}
+	if podRevision == revision {
+		return true
+	}
+	return shortHash(podRevision) == shortHash(revision)
+}
+
+func shortHash(revision string) string {
+	parts := strings.Split(revision, "-")
+	return parts[len(parts)-1]
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/openkruise/kruise-api/apps/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func newRevision(name string, revision int64, image string) *appsv1.ControllerRevision {
+	return &appsv1.ControllerRevision{
+		ObjectMeta: v1.ObjectMeta{Name: name, Namespace: "default"},
+		Revision:   revision,
+		Data: runtime.RawExtension{Raw: []byte(`{"spec":{"template":{"$patch":"replace","metadata":{"labels":{"app":"web"}},` +
+			`"spec":{"initContainers":[{"name":"init","image":"busybox"}],"containers":[{"name":"main","image":"` + image + `"}]}}}}`)},
+	}
+}
+
+func TestTemplateFromRevision(t *testing.T) {
+	template, err := TemplateFromRevision(newRevision("web-v1", 1, "nginx:1.24"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"app": "web"}, template.Labels)
+	assert.Equal(t, []ContainerImage{
+		{Container: "init", Image: "busybox", Init: true},
+		{Container: "main", Image: "nginx:1.24"},
+	}, containerImages(template))
+
+	_, err = TemplateFromRevision(&appsv1.ControllerRevision{
+		ObjectMeta: v1.ObjectMeta{Name: "web-v0"},
+		Data:       runtime.RawExtension{Raw: []byte(`{"spec":{}}`)},
+	})
+	assert.Error(t, err)
+}
+
+func TestPreviousRevision(t *testing.T) {
+	revisions := []*appsv1.ControllerRevision{
+		newRevision("web-v1", 1, "nginx:1.23"),
+		newRevision("web-v2", 2, "nginx:1.24"),
+		newRevision("web-v3", 3, "nginx:1.25"),
+	}
+	cloneSet := func(current, update string) *v1alpha1.CloneSet {
+		return &v1alpha1.CloneSet{Status: v1alpha1.CloneSetStatus{CurrentRevision: current, UpdateRevision: update}}
+	}
+
+	// while rolling out, the pods not yet updated run the current revision
+	assert.Equal(t, "web-v1", PreviousRevision(cloneSet("web-v1", "web-v3"), revisions).Name)
+	// after a rollout, the newest other revision
+	assert.Equal(t, "web-v2", PreviousRevision(cloneSet("web-v3", "web-v3"), revisions).Name)
+	// after a rollback to an older revision
+	assert.Equal(t, "web-v3", PreviousRevision(cloneSet("web-v1", "web-v1"), revisions).Name)
+	assert.Nil(t, PreviousRevision(cloneSet("web-v1", "web-v1"), revisions[:1]))
+}
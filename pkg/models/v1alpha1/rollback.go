@@ -0,0 +1,166 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/constants"
+	"github.com/openkruise/kruise-api/apps/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	defaultProgressDeadlineSeconds = 600
+	defaultMaxRestarts             = 3
+
+	RollbackReasonCrashLoop        = "CrashLoop"
+	RollbackReasonProgressDeadline = "ProgressDeadlineExceeded"
+)
+
+// RollbackPolicy enables the automatic rollback of a CloneSet whose rollout fails. It is stored in
+// the RollbackPolicyAnnotation of the CloneSet and carried out by the rollback controller.
+type RollbackPolicy struct {
+	// ProgressDeadlineSeconds is how long the rollout may go without another updated pod becoming ready, defaults to 600.
+	ProgressDeadlineSeconds int32 `json:"progressDeadlineSeconds,omitempty" description:"seconds the rollout may go without progress, defaults to 600"`
+	// MaxRestarts is the container restart count that marks an updated pod as crash-looping, defaults to 3.
+	MaxRestarts int32 `json:"maxRestarts,omitempty" description:"container restarts of an updated pod that fail the rollout, defaults to 3"`
+}
+
+// RollbackStatus tracks the progress of the rollout watched for failures and the last rollback, stored
+// in the RollbackStatusAnnotation of the CloneSet.
+type RollbackStatus struct {
+	UpdateRevision       string    `json:"updateRevision,omitempty" description:"revision being rolled out"`
+	TargetReplicas       int32     `json:"targetReplicas" description:"pods to update, the replicas above the partition"`
+	UpdatedReadyReplicas int32     `json:"updatedReadyReplicas" description:"ready pods at the update revision"`
+	Paused               bool      `json:"paused,omitempty" description:"whether the rollout is paused"`
+	LastProgressTime     *v1.Time  `json:"lastProgressTime,omitempty" description:"last time the rollout made progress or was changed"`
+	LastRollback         *Rollback `json:"lastRollback,omitempty" description:"the last automatic rollback"`
+	// RestartBaselines are the restart counts of the containers of updated pods, keyed by pod/container,
+	// when the pods moved to the update revision.
+	RestartBaselines map[string]int32 `json:"restartBaselines,omitempty" description:"restart counts of updated containers when their pods moved to the update revision"`
+}
+
+// Rollback records why a CloneSet was rolled back.
+type Rollback struct {
+	FromRevision string  `json:"fromRevision" description:"revision that failed"`
+	ToRevision   string  `json:"toRevision" description:"revision the template was restored from"`
+	Reason       string  `json:"reason" description:"CrashLoop or ProgressDeadlineExceeded"`
+	Message      string  `json:"message" description:"details of the failure"`
+	Time         v1.Time `json:"time" description:"time of the rollback"`
+}
+
+// CloneSetAutoRollback is the rollback policy of a CloneSet and its status.
+type CloneSetAutoRollback struct {
+	Policy RollbackPolicy `json:"policy" description:"the rollback policy"`
+	Status RollbackStatus `json:"status" description:"the watched rollout and the last rollback"`
+}
+
+// SetCloneSetRollbackPolicy enables the automatic rollback of the CloneSet.
+func (c *operator) SetCloneSetRollbackPolicy(namespace, name string, policy *RollbackPolicy) (*CloneSetAutoRollback, error) {
+	if policy.ProgressDeadlineSeconds < 0 || policy.MaxRestarts < 0 {
+		return nil, errors.NewBadRequest("progressDeadlineSeconds and maxRestarts must not be negative")
+	}
+	policyData, err := json.Marshal(policy)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				constants.RollbackPolicyAnnotation: string(policyData),
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cloneSet, err := c.kruiseclientset.AppsV1alpha1().CloneSets(namespace).Patch(context.Background(), name, types.MergePatchType, patch, v1.PatchOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return cloneSetAutoRollback(cloneSet)
+}
+
+// GetCloneSetRollbackPolicy returns the rollback policy of the CloneSet and the last rollback.
+func (c *operator) GetCloneSetRollbackPolicy(namespace, name string) (*CloneSetAutoRollback, error) {
+	cloneSet, err := c.kruiseclientset.AppsV1alpha1().CloneSets(namespace).Get(context.Background(), name, v1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return cloneSetAutoRollback(cloneSet)
+}
+
+// DeleteCloneSetRollbackPolicy disables the automatic rollback of the CloneSet.
+func (c *operator) DeleteCloneSetRollbackPolicy(namespace, name string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				constants.RollbackPolicyAnnotation: nil,
+				constants.RollbackStatusAnnotation: nil,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = c.kruiseclientset.AppsV1alpha1().CloneSets(namespace).Patch(context.Background(), name, types.MergePatchType, patch, v1.PatchOptions{})
+	return err
+}
+
+// GetRollbackPolicy reads the rollback policy, with defaults applied, and the rollback status from the
+// annotations of the CloneSet. It returns a nil policy if the CloneSet has none.
+func GetRollbackPolicy(cloneSet *v1alpha1.CloneSet) (*RollbackPolicy, *RollbackStatus, error) {
+	policyData, ok := cloneSet.Annotations[constants.RollbackPolicyAnnotation]
+	if !ok {
+		return nil, nil, nil
+	}
+	policy := &RollbackPolicy{}
+	if err := json.Unmarshal([]byte(policyData), policy); err != nil {
+		return nil, nil, fmt.Errorf("invalid rollback policy of cloneset %s: %v", cloneSet.Name, err)
+	}
+	if policy.ProgressDeadlineSeconds <= 0 {
+		policy.ProgressDeadlineSeconds = defaultProgressDeadlineSeconds
+	}
+	if policy.MaxRestarts <= 0 {
+		policy.MaxRestarts = defaultMaxRestarts
+	}
+
+	status := &RollbackStatus{}
+	if statusData, ok := cloneSet.Annotations[constants.RollbackStatusAnnotation]; ok {
+		if err := json.Unmarshal([]byte(statusData), status); err != nil {
+			return nil, nil, fmt.Errorf("invalid rollback status of cloneset %s: %v", cloneSet.Name, err)
+		}
+	}
+	return policy, status, nil
+}
+
+func cloneSetAutoRollback(cloneSet *v1alpha1.CloneSet) (*CloneSetAutoRollback, error) {
+	policy, status, err := GetRollbackPolicy(cloneSet)
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		return nil, errors.NewNotFound(v1alpha1.Resource("rollbackpolicies"), cloneSet.Name)
+	}
+	return &CloneSetAutoRollback{Policy: *policy, Status: *status}, nil
+}
@@ -0,0 +1,369 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/constants"
+	"github.com/openkruise/kruise-api/apps/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/util/retry"
+)
+
+// RolloutStep moves the partition of a CloneSet, either to an absolute value or by a number of pods.
+type RolloutStep struct {
+	// Partition is the number or percentage of pods kept at the current revision, e.g. 3 or "30%".
+	Partition *intstr.IntOrString `json:"partition,omitempty" description:"number or percentage of pods kept at the current revision"`
+	// Advance updates this many more pods by lowering the partition, it is ignored if Partition is set.
+	Advance int32 `json:"advance,omitempty" description:"number of pods to update in addition to the already updated ones"`
+}
+
+// CloneSetRollout is the progress of a partitioned CloneSet rollout.
+type CloneSetRollout struct {
+	Replicas             int32               `json:"replicas" description:"desired replicas"`
+	Partition            *intstr.IntOrString `json:"partition,omitempty" description:"number or percentage of pods kept at the current revision"`
+	Paused               bool                `json:"paused" description:"whether the rollout is paused"`
+	UpdatedReplicas      int32               `json:"updatedReplicas" description:"pods at the update revision"`
+	UpdatedReadyReplicas int32               `json:"updatedReadyReplicas" description:"ready pods at the update revision"`
+	ReadyReplicas        int32               `json:"readyReplicas" description:"ready pods"`
+	CurrentRevision      string              `json:"currentRevision" description:"revision the rollout started from"`
+	UpdateRevision       string              `json:"updateRevision" description:"revision being rolled out"`
+}
+
+const (
+	RolloutProgressing = "Progressing"
+	RolloutWaiting     = "Waiting"
+	RolloutPaused      = "Paused"
+	RolloutCompleted   = "Completed"
+)
+
+// RolloutPlan drives a CloneSet rollout through steps. It is stored in the RolloutPlanAnnotation of the
+// CloneSet and carried out by the rollout controller. Once a revision is rolled out, the controller keeps the
+// partition at the replicas so that the next revision starts from the first step.
+type RolloutPlan struct {
+	// Steps are the numbers or percentages of pods updated at each step, e.g. "10%", "30%", "100%".
+	Steps []intstr.IntOrString `json:"steps" description:"numbers or percentages of pods updated at each step"`
+	// WaitSeconds is how long the updated pods of a step stay ready before the next step starts.
+	WaitSeconds int32 `json:"waitSeconds,omitempty" description:"seconds to wait after the pods of a step are ready"`
+	// MinReadyPercent pauses the rollout while fewer than this percentage of the replicas are ready.
+	MinReadyPercent int32 `json:"minReadyPercent,omitempty" description:"pause the rollout while the ready percentage is below this value"`
+}
+
+// RolloutPlanStatus is the progress of a RolloutPlan, stored in the RolloutStatusAnnotation of the CloneSet.
+type RolloutPlanStatus struct {
+	Phase          string   `json:"phase" description:"Progressing, Waiting, Paused or Completed"`
+	CurrentStep    int32    `json:"currentStep" description:"index of the current step"`
+	UpdateRevision string   `json:"updateRevision,omitempty" description:"revision rolled out by the plan"`
+	StepReadyTime  *v1.Time `json:"stepReadyTime,omitempty" description:"time the pods of the current step became ready"`
+	Message        string   `json:"message,omitempty" description:"reason the rollout is paused"`
+}
+
+// CloneSetRolloutPlan is the rollout plan of a CloneSet and its progress.
+type CloneSetRolloutPlan struct {
+	Plan   RolloutPlan       `json:"plan" description:"the rollout plan"`
+	Status RolloutPlanStatus `json:"status" description:"progress of the rollout plan"`
+}
+
+// PauseCloneSetRollout pauses the rollout of the CloneSet. A rollout plan pauses and resumes the
+// CloneSet itself, so the rollout cannot be paused while it has one.
+func (c *operator) PauseCloneSetRollout(namespace, name string) (*CloneSetRollout, error) {
+	return c.patchCloneSetUpdateStrategy(namespace, name, func(cloneSet *v1alpha1.CloneSet) (map[string]interface{}, error) {
+		if err := rolloutPlanConflict(cloneSet); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"paused": true}, nil
+	})
+}
+
+// ResumeCloneSetRollout resumes the rollout of the CloneSet, unless a rollout plan drives it.
+func (c *operator) ResumeCloneSetRollout(namespace, name string) (*CloneSetRollout, error) {
+	return c.patchCloneSetUpdateStrategy(namespace, name, func(cloneSet *v1alpha1.CloneSet) (map[string]interface{}, error) {
+		if err := rolloutPlanConflict(cloneSet); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"paused": false}, nil
+	})
+}
+
+// StepCloneSetRollout sets the partition of the CloneSet, or lowers it to update more pods.
+func (c *operator) StepCloneSetRollout(namespace, name string, step *RolloutStep) (*CloneSetRollout, error) {
+	if step.Partition == nil && step.Advance <= 0 {
+		return nil, errors.NewBadRequest("either partition or a positive advance must be set")
+	}
+
+	return c.patchCloneSetUpdateStrategy(namespace, name, func(cloneSet *v1alpha1.CloneSet) (map[string]interface{}, error) {
+		if err := rolloutPlanConflict(cloneSet); err != nil {
+			return nil, err
+		}
+		if step.Partition != nil {
+			if _, err := intstr.GetScaledValueFromIntOrPercent(step.Partition, int(cloneSetReplicas(cloneSet)), true); err != nil {
+				return nil, errors.NewBadRequest(fmt.Sprintf("invalid partition %s: %v", step.Partition.String(), err))
+			}
+			return map[string]interface{}{"partition": step.Partition}, nil
+		}
+
+		partition, err := advancePartition(cloneSet.Spec.UpdateStrategy.Partition, cloneSetReplicas(cloneSet), step.Advance)
+		if err != nil {
+			return nil, errors.NewBadRequest(err.Error())
+		}
+		return map[string]interface{}{"partition": partition}, nil
+	})
+}
+
+// SetCloneSetRolloutPlan starts driving the rollout of the CloneSet through the steps of the plan,
+// beginning with the partition of the first step.
+func (c *operator) SetCloneSetRolloutPlan(namespace, name string, plan *RolloutPlan) (*CloneSetRolloutPlan, error) {
+	if err := validateRolloutPlan(plan); err != nil {
+		return nil, errors.NewBadRequest(err.Error())
+	}
+	planData, err := json.Marshal(plan)
+	if err != nil {
+		return nil, err
+	}
+
+	var patched *v1alpha1.CloneSet
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cloneSet, err := c.kruiseclientset.AppsV1alpha1().CloneSets(namespace).Get(context.Background(), name, v1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		status := RolloutPlanStatus{
+			Phase:          RolloutProgressing,
+			UpdateRevision: cloneSet.Status.UpdateRevision,
+		}
+		statusData, err := json.Marshal(status)
+		if err != nil {
+			return err
+		}
+		patch, err := json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"resourceVersion": cloneSet.ResourceVersion,
+				"annotations": map[string]interface{}{
+					constants.RolloutPlanAnnotation:   string(planData),
+					constants.RolloutStatusAnnotation: string(statusData),
+				},
+			},
+			"spec": map[string]interface{}{
+				"updateStrategy": map[string]interface{}{
+					"partition": StepPartition(plan.Steps[0], cloneSetReplicas(cloneSet)),
+					"paused":    false,
+				},
+			},
+		})
+		if err != nil {
+			return err
+		}
+		patched, err = c.kruiseclientset.AppsV1alpha1().CloneSets(namespace).Patch(context.Background(), name, types.MergePatchType, patch, v1.PatchOptions{})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cloneSetRolloutPlan(patched)
+}
+
+// GetCloneSetRolloutPlan returns the rollout plan of the CloneSet and its progress.
+func (c *operator) GetCloneSetRolloutPlan(namespace, name string) (*CloneSetRolloutPlan, error) {
+	cloneSet, err := c.kruiseclientset.AppsV1alpha1().CloneSets(namespace).Get(context.Background(), name, v1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return cloneSetRolloutPlan(cloneSet)
+}
+
+// DeleteCloneSetRolloutPlan stops driving the rollout of the CloneSet, leaving its partition as it is.
+func (c *operator) DeleteCloneSetRolloutPlan(namespace, name string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				constants.RolloutPlanAnnotation:   nil,
+				constants.RolloutStatusAnnotation: nil,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = c.kruiseclientset.AppsV1alpha1().CloneSets(namespace).Patch(context.Background(), name, types.MergePatchType, patch, v1.PatchOptions{})
+	return err
+}
+
+// GetRolloutPlan reads the rollout plan and its progress from the annotations of the CloneSet,
+// it returns a nil plan if the CloneSet has none.
+func GetRolloutPlan(cloneSet *v1alpha1.CloneSet) (*RolloutPlan, *RolloutPlanStatus, error) {
+	planData, ok := cloneSet.Annotations[constants.RolloutPlanAnnotation]
+	if !ok {
+		return nil, nil, nil
+	}
+	plan := &RolloutPlan{}
+	if err := json.Unmarshal([]byte(planData), plan); err != nil {
+		return nil, nil, fmt.Errorf("invalid rollout plan of cloneset %s: %v", cloneSet.Name, err)
+	}
+	if err := validateRolloutPlan(plan); err != nil {
+		return nil, nil, fmt.Errorf("invalid rollout plan of cloneset %s: %v", cloneSet.Name, err)
+	}
+
+	status := &RolloutPlanStatus{Phase: RolloutProgressing}
+	if statusData, ok := cloneSet.Annotations[constants.RolloutStatusAnnotation]; ok {
+		if err := json.Unmarshal([]byte(statusData), status); err != nil {
+			return nil, nil, fmt.Errorf("invalid rollout status of cloneset %s: %v", cloneSet.Name, err)
+		}
+	}
+	if status.CurrentStep < 0 || int(status.CurrentStep) >= len(plan.Steps) {
+		status.CurrentStep = 0
+	}
+	return plan, status, nil
+}
+
+// StepPartition is the partition that leaves the pods beyond the step at the current revision.
+func StepPartition(step intstr.IntOrString, replicas int32) int32 {
+	updated, err := intstr.GetScaledValueFromIntOrPercent(&step, int(replicas), true)
+	if err != nil || updated > int(replicas) {
+		updated = int(replicas)
+	}
+	if updated < 0 {
+		updated = 0
+	}
+	return replicas - int32(updated)
+}
+
+func validateRolloutPlan(plan *RolloutPlan) error {
+	if len(plan.Steps) == 0 {
+		return fmt.Errorf("rollout plan has no steps")
+	}
+	if plan.WaitSeconds < 0 {
+		return fmt.Errorf("waitSeconds %d must not be negative", plan.WaitSeconds)
+	}
+	if plan.MinReadyPercent < 0 || plan.MinReadyPercent > 100 {
+		return fmt.Errorf("minReadyPercent %d must be between 0 and 100", plan.MinReadyPercent)
+	}
+	for i := range plan.Steps {
+		// resolve against 100 replicas so percentages and numbers are both checked
+		if _, err := intstr.GetScaledValueFromIntOrPercent(&plan.Steps[i], 100, true); err != nil {
+			return fmt.Errorf("invalid step %s: %v", plan.Steps[i].String(), err)
+		}
+	}
+	return nil
+}
+
+func cloneSetRolloutPlan(cloneSet *v1alpha1.CloneSet) (*CloneSetRolloutPlan, error) {
+	plan, status, err := GetRolloutPlan(cloneSet)
+	if err != nil {
+		return nil, err
+	}
+	if plan == nil {
+		return nil, errors.NewNotFound(v1alpha1.Resource("rolloutplans"), cloneSet.Name)
+	}
+	return &CloneSetRolloutPlan{Plan: *plan, Status: *status}, nil
+}
+
+// patchCloneSetUpdateStrategy merges the fields returned by update into spec.updateStrategy of the
+// CloneSet, guarded by the resource version the fields were computed from.
+func (c *operator) patchCloneSetUpdateStrategy(namespace, name string, update func(cloneSet *v1alpha1.CloneSet) (map[string]interface{}, error)) (*CloneSetRollout, error) {
+	var patched *v1alpha1.CloneSet
+	// errors of update, a conflict among them, are not retried
+	var updateErr error
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cloneSet, err := c.kruiseclientset.AppsV1alpha1().CloneSets(namespace).Get(context.Background(), name, v1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		var updateStrategy map[string]interface{}
+		updateStrategy, updateErr = update(cloneSet)
+		if updateErr != nil {
+			return nil
+		}
+		patch, err := json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"resourceVersion": cloneSet.ResourceVersion,
+			},
+			"spec": map[string]interface{}{
+				"updateStrategy": updateStrategy,
+			},
+		})
+		if err != nil {
+			return err
+		}
+		patched, err = c.kruiseclientset.AppsV1alpha1().CloneSets(namespace).Patch(context.Background(), name, types.MergePatchType, patch, v1.PatchOptions{})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if updateErr != nil {
+		return nil, updateErr
+	}
+	return cloneSetRollout(patched), nil
+}
+
+// rolloutPlanConflict rejects changes to the update strategy of a CloneSet driven by a rollout plan.
+func rolloutPlanConflict(cloneSet *v1alpha1.CloneSet) error {
+	if _, ok := cloneSet.Annotations[constants.RolloutPlanAnnotation]; ok {
+		return errors.NewConflict(v1alpha1.Resource(constants.CloneSetType), cloneSet.Name, fmt.Errorf("the rollout is driven by a rollout plan, delete the plan first"))
+	}
+	return nil
+}
+
+// advancePartition lowers the partition by advance pods, a percentage partition is resolved against
+// the replicas first. The partition never goes below zero.
+func advancePartition(partition *intstr.IntOrString, replicas, advance int32) (intstr.IntOrString, error) {
+	current := 0
+	if partition != nil {
+		var err error
+		current, err = intstr.GetScaledValueFromIntOrPercent(partition, int(replicas), true)
+		if err != nil {
+			return intstr.IntOrString{}, fmt.Errorf("invalid partition %s: %v", partition.String(), err)
+		}
+	}
+	if current > int(replicas) {
+		current = int(replicas)
+	}
+
+	next := current - int(advance)
+	if next < 0 {
+		next = 0
+	}
+	return intstr.FromInt(next), nil
+}
+
+func cloneSetReplicas(cloneSet *v1alpha1.CloneSet) int32 {
+	if cloneSet.Spec.Replicas == nil {
+		return 1
+	}
+	return *cloneSet.Spec.Replicas
+}
+
+func cloneSetRollout(cloneSet *v1alpha1.CloneSet) *CloneSetRollout {
+	return &CloneSetRollout{
+		Replicas:             cloneSetReplicas(cloneSet),
+		Partition:            cloneSet.Spec.UpdateStrategy.Partition,
+		Paused:               cloneSet.Spec.UpdateStrategy.Paused,
+		UpdatedReplicas:      cloneSet.Status.UpdatedReplicas,
+		UpdatedReadyReplicas: cloneSet.Status.UpdatedReadyReplicas,
+		ReadyReplicas:        cloneSet.Status.ReadyReplicas,
+		CurrentRevision:      cloneSet.Status.CurrentRevision,
+		UpdateRevision:       cloneSet.Status.UpdateRevision,
+	}
+}
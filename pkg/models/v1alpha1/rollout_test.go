@@ -0,0 +1,128 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/constants"
+	"github.com/openkruise/kruise-api/apps/v1alpha1"
+	kruisefake "github.com/openkruise/kruise-api/client/clientset/versioned/fake"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestAdvancePartition(t *testing.T) {
+	number := intstr.FromInt(8)
+	percent := intstr.FromString("50%")
+	overflow := intstr.FromInt(20)
+	invalid := intstr.FromString("half")
+	tests := []struct {
+		name      string
+		partition *intstr.IntOrString
+		advance   int32
+		want      intstr.IntOrString
+		wantErr   bool
+	}{
+		{name: "number", partition: &number, advance: 3, want: intstr.FromInt(5)},
+		{name: "percentage", partition: &percent, advance: 2, want: intstr.FromInt(3)},
+		{name: "partition above replicas", partition: &overflow, advance: 4, want: intstr.FromInt(6)},
+		{name: "no partition", partition: nil, advance: 2, want: intstr.FromInt(0)},
+		{name: "advance past zero", partition: &number, advance: 20, want: intstr.FromInt(0)},
+		{name: "invalid partition", partition: &invalid, advance: 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := advancePartition(tt.partition, 10, tt.advance)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCloneSetRolloutWithPlan(t *testing.T) {
+	partition := intstr.FromInt(4)
+	newCloneSet := func(paused bool, annotations map[string]string) *v1alpha1.CloneSet {
+		return &v1alpha1.CloneSet{
+			ObjectMeta: v1.ObjectMeta{Name: "web", Namespace: "default", Annotations: annotations},
+			Spec: v1alpha1.CloneSetSpec{
+				UpdateStrategy: v1alpha1.CloneSetUpdateStrategy{Partition: &partition, Paused: paused},
+			},
+		}
+	}
+	planned := map[string]string{constants.RolloutPlanAnnotation: `{"steps":[{"partition":4}]}`}
+	step := &RolloutStep{Advance: 1}
+
+	tests := []struct {
+		name       string
+		paused     bool
+		change     func(c *operator) (*CloneSetRollout, error)
+		wantPaused bool
+	}{
+		{
+			name: "pause",
+			change: func(c *operator) (*CloneSetRollout, error) {
+				return c.PauseCloneSetRollout("default", "web")
+			},
+			wantPaused: true,
+		},
+		{
+			name:   "resume",
+			paused: true,
+			change: func(c *operator) (*CloneSetRollout, error) {
+				return c.ResumeCloneSetRollout("default", "web")
+			},
+			wantPaused: false,
+		},
+		{
+			name: "step",
+			change: func(c *operator) (*CloneSetRollout, error) {
+				return c.StepCloneSetRollout("default", "web", step)
+			},
+			wantPaused: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name+" without a plan", func(t *testing.T) {
+			c := &operator{kruiseclientset: kruisefake.NewSimpleClientset(newCloneSet(tt.paused, nil))}
+			rollout, err := tt.change(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantPaused, rollout.Paused)
+		})
+
+		t.Run(tt.name+" with a plan", func(t *testing.T) {
+			kruiseclient := kruisefake.NewSimpleClientset(newCloneSet(tt.paused, planned))
+			c := &operator{kruiseclientset: kruiseclient}
+			_, err := tt.change(c)
+			assert.True(t, errors.IsConflict(err), "unexpected error %v", err)
+			// the conflict is not retried and nothing is patched
+			assert.Len(t, kruiseclient.Actions(), 1)
+
+			cloneSet, err := kruiseclient.AppsV1alpha1().CloneSets("default").Get(context.Background(), "web", v1.GetOptions{})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.paused, cloneSet.Spec.UpdateStrategy.Paused)
+			assert.Equal(t, &partition, cloneSet.Spec.UpdateStrategy.Partition)
+		})
+	}
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/constants"
+	"github.com/duke-git/lancet/v2/slice"
+	"github.com/openkruise/kruise-api/apps/v1alpha1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+)
+
+// CloneSetScale sets the replicas of a CloneSet.
+type CloneSetScale struct {
+	Replicas     *int32   `json:"replicas" description:"desired replicas of the cloneset, required"`
+	PodsToDelete []string `json:"podsToDelete,omitempty" description:"pods of the cloneset to remove first on scale-in"`
+}
+
+// DeleteCloneSetPods removes specific pods of a CloneSet.
+type DeleteCloneSetPods struct {
+	Pods []string `json:"pods" description:"names of the pods of the cloneset to delete"`
+	// DecrementReplicas scales the CloneSet in by the pods to delete, otherwise they are recreated.
+	DecrementReplicas bool `json:"decrementReplicas,omitempty" description:"decrement the replicas by the number of deleted pods so they are not recreated"`
+}
+
+// GetCloneSetScale reads the scale subresource of the CloneSet.
+func (c *operator) GetCloneSetScale(namespace, name string) (*autoscalingv1.Scale, error) {
+	return c.kruiseclientset.AppsV1alpha1().CloneSets(namespace).GetScale(context.Background(), name, v1.GetOptions{})
+}
+
+// ScaleCloneSet sets the replicas of the CloneSet through the scale subresource. The scale subresource
+// can not carry podsToDelete, so when pods are given the replicas and scaleStrategy.podsToDelete are
+// set in a single patch of the CloneSet instead, to make sure the controller removes those pods.
+func (c *operator) ScaleCloneSet(namespace, name string, scale *CloneSetScale) (*autoscalingv1.Scale, error) {
+	if scale.Replicas == nil {
+		return nil, errors.NewBadRequest("replicas is required")
+	}
+	replicas := *scale.Replicas
+	if replicas < 0 {
+		return nil, errors.NewBadRequest(fmt.Sprintf("replicas %d must not be negative", replicas))
+	}
+
+	if len(scale.PodsToDelete) == 0 {
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			current, err := c.GetCloneSetScale(namespace, name)
+			if err != nil {
+				return err
+			}
+			current.Spec.Replicas = replicas
+			_, err = c.kruiseclientset.AppsV1alpha1().CloneSets(namespace).UpdateScale(context.Background(), name, current, v1.UpdateOptions{})
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		return c.GetCloneSetScale(namespace, name)
+	}
+
+	if err := c.validateCloneSetPods(namespace, name, scale.PodsToDelete); err != nil {
+		return nil, err
+	}
+	if _, err := c.addPodsToDelete(namespace, name, scale.PodsToDelete, func(*v1alpha1.CloneSet) int32 { return replicas }); err != nil {
+		return nil, err
+	}
+	return c.GetCloneSetScale(namespace, name)
+}
+
+// DeleteCloneSetPods appends the pods, validated against the pods of the CloneSet, to its
+// scaleStrategy.podsToDelete, and optionally decrements the replicas by the newly added pods.
+func (c *operator) DeleteCloneSetPods(namespace, name string, pods *DeleteCloneSetPods) (*v1alpha1.CloneSet, error) {
+	if len(pods.Pods) == 0 {
+		return nil, errors.NewBadRequest("no pods to delete")
+	}
+	podNames := slice.Unique(pods.Pods)
+	if err := c.validateCloneSetPods(namespace, name, podNames); err != nil {
+		return nil, err
+	}
+
+	return c.addPodsToDelete(namespace, name, podNames, func(cloneSet *v1alpha1.CloneSet) int32 {
+		return replicasAfterDelete(cloneSet, podNames, pods.DecrementReplicas)
+	})
+}
+
+// replicasAfterDelete is the replicas of the CloneSet once the pods are deleted: unchanged, or decremented
+// by the pods that are not already in scaleStrategy.podsToDelete, which were accounted for before.
+func replicasAfterDelete(cloneSet *v1alpha1.CloneSet, podNames []string, decrement bool) int32 {
+	replicas := int32(1)
+	if cloneSet.Spec.Replicas != nil {
+		replicas = *cloneSet.Spec.Replicas
+	}
+	if !decrement {
+		return replicas
+	}
+	for _, podName := range podNames {
+		if replicas > 0 && !slice.Contain(cloneSet.Spec.ScaleStrategy.PodsToDelete, podName) {
+			replicas--
+		}
+	}
+	return replicas
+}
+
+// validateCloneSetPods makes sure every named pod is owned by the CloneSet. ListPods resolves pods by the
+// selector, which can also match pods of other workloads, e.g. of a Deployment migrated to the CloneSet.
+func (c *operator) validateCloneSetPods(namespace, name string, podNames []string) error {
+	obj, err := c.resourceGetter.Get(constants.CloneSetType, namespace, name)
+	if err != nil {
+		return err
+	}
+	pods, err := c.ListPods(namespace, constants.CloneSetType, name)
+	if err != nil {
+		return err
+	}
+	return checkOwnedPods(obj.(*v1alpha1.CloneSet), pods.Items, podNames)
+}
+
+func checkOwnedPods(cloneSet *v1alpha1.CloneSet, pods []interface{}, podNames []string) error {
+	owned := make([]string, 0, len(pods))
+	for _, item := range pods {
+		if pod := item.(*corev1.Pod); isOwnedBy(pod.ObjectMeta, cloneSet.UID) {
+			owned = append(owned, pod.Name)
+		}
+	}
+	for _, podName := range podNames {
+		if !slice.Contain(owned, podName) {
+			return errors.NewBadRequest(fmt.Sprintf("pod %s does not belong to cloneset %s", podName, cloneSet.Name))
+		}
+	}
+	return nil
+}
+
+// addPodsToDelete appends the pods to scaleStrategy.podsToDelete of the CloneSet and sets the replicas
+// computed from the current CloneSet, in one patch guarded by the resource version.
+func (c *operator) addPodsToDelete(namespace, name string, podNames []string, replicas func(cloneSet *v1alpha1.CloneSet) int32) (*v1alpha1.CloneSet, error) {
+	var patched *v1alpha1.CloneSet
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cloneSet, err := c.kruiseclientset.AppsV1alpha1().CloneSets(namespace).Get(context.Background(), name, v1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		podsToDelete := append([]string{}, cloneSet.Spec.ScaleStrategy.PodsToDelete...)
+		for _, podName := range podNames {
+			if !slice.Contain(podsToDelete, podName) {
+				podsToDelete = append(podsToDelete, podName)
+			}
+		}
+
+		patch, err := json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"resourceVersion": cloneSet.ResourceVersion,
+			},
+			"spec": map[string]interface{}{
+				"replicas": replicas(cloneSet),
+				"scaleStrategy": map[string]interface{}{
+					"podsToDelete": podsToDelete,
+				},
+			},
+		})
+		if err != nil {
+			return err
+		}
+		patched, err = c.kruiseclientset.AppsV1alpha1().CloneSets(namespace).Patch(context.Background(), name, types.MergePatchType, patch, v1.PatchOptions{})
+		return err
+	})
+	return patched, err
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/openkruise/kruise-api/apps/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestScaleCloneSetRequiresReplicas(t *testing.T) {
+	for _, body := range []string{`{}`, `{"podsToDelete":["web-a"]}`} {
+		scale := &CloneSetScale{}
+		assert.NoError(t, json.Unmarshal([]byte(body), scale))
+		assert.Nil(t, scale.Replicas)
+
+		// the body is rejected before the cloneset is read, so no clients are needed
+		_, err := (&operator{}).ScaleCloneSet("default", "web", scale)
+		assert.True(t, errors.IsBadRequest(err), body)
+	}
+
+	replicas := int32(-1)
+	_, err := (&operator{}).ScaleCloneSet("default", "web", &CloneSetScale{Replicas: &replicas})
+	assert.True(t, errors.IsBadRequest(err))
+}
+
+func TestReplicasAfterDelete(t *testing.T) {
+	replicas := int32(3)
+	cloneSet := &v1alpha1.CloneSet{Spec: v1alpha1.CloneSetSpec{
+		Replicas:      &replicas,
+		ScaleStrategy: v1alpha1.CloneSetScaleStrategy{PodsToDelete: []string{"web-a"}},
+	}}
+
+	tests := []struct {
+		name      string
+		podNames  []string
+		decrement bool
+		want      int32
+	}{
+		{
+			name:     "keeps the replicas so the pods are recreated",
+			podNames: []string{"web-b", "web-c"},
+			want:     3,
+		},
+		{
+			name:      "decrements by the deleted pods",
+			podNames:  []string{"web-b", "web-c"},
+			decrement: true,
+			want:      1,
+		},
+		{
+			name:      "does not decrement again for pods already to delete",
+			podNames:  []string{"web-a", "web-b"},
+			decrement: true,
+			want:      2,
+		},
+		{
+			name:      "never goes below zero",
+			podNames:  []string{"web-b", "web-c", "web-d", "web-e"},
+			decrement: true,
+			want:      0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, replicasAfterDelete(cloneSet, tt.podNames, tt.decrement))
+		})
+	}
+}
+
+func TestCheckOwnedPods(t *testing.T) {
+	cloneSet := &v1alpha1.CloneSet{ObjectMeta: v1.ObjectMeta{Name: "web", UID: "cloneset-uid"}}
+	pod := func(name, ownerUID string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: v1.ObjectMeta{Name: name, OwnerReferences: []v1.OwnerReference{{UID: types.UID(ownerUID)}}}}
+	}
+	// pods of a Deployment migrated to the CloneSet match its selector too
+	pods := []interface{}{pod("web-a", "cloneset-uid"), pod("web-b", "cloneset-uid"), pod("web-7c9b2-x", "replicaset-uid")}
+
+	assert.NoError(t, checkOwnedPods(cloneSet, pods, []string{"web-a", "web-b"}))
+	err := checkOwnedPods(cloneSet, pods, []string{"web-a", "web-7c9b2-x"})
+	assert.True(t, errors.IsBadRequest(err))
+	err = checkOwnedPods(cloneSet, pods, []string{"web-gone"})
+	assert.True(t, errors.IsBadRequest(err))
+}
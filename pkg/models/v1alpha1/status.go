@@ -0,0 +1,183 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/constants"
+	appspub "github.com/openkruise/kruise-api/apps/pub"
+	"github.com/openkruise/kruise-api/apps/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/klog/v2"
+)
+
+const (
+	InPlaceUpdating = "Updating"
+	InPlaceUpdated  = "Updated"
+)
+
+// CloneSetRolloutStatus is the detailed rollout progress of a CloneSet down to its pods.
+type CloneSetRolloutStatus struct {
+	CloneSetRollout `json:",inline"`
+
+	Phase                   string                       `json:"phase" description:"Progressing, Waiting for the partition to be lowered, Paused or Completed"`
+	AvailableReplicas       int32                        `json:"availableReplicas" description:"available pods"`
+	ExpectedUpdatedReplicas int32                        `json:"expectedUpdatedReplicas" description:"pods expected at the update revision with the current partition"`
+	PartitionReplicas       int32                        `json:"partitionReplicas" description:"pods kept at the current revision by the partition"`
+	Generation              int64                        `json:"generation" description:"generation of the cloneset spec"`
+	ObservedGeneration      int64                        `json:"observedGeneration" description:"generation observed by the kruise controller"`
+	GenerationLag           int64                        `json:"generationLag" description:"spec generations not yet observed by the kruise controller"`
+	Conditions              []v1alpha1.CloneSetCondition `json:"conditions,omitempty" description:"conditions of the cloneset"`
+	Pods                    []PodRevision                `json:"pods" description:"revision and update state of each pod"`
+}
+
+// PodRevision is the revision of a pod of a CloneSet and the state of its in-place update.
+type PodRevision struct {
+	Name          string               `json:"name" description:"name of the pod"`
+	Revision      string               `json:"revision" description:"revision the pod was created from or updated to"`
+	Updated       bool                 `json:"updated" description:"whether the pod is at the update revision"`
+	Ready         bool                 `json:"ready" description:"whether the pod is ready"`
+	Phase         corev1.PodPhase      `json:"phase" description:"phase of the pod"`
+	InPlaceUpdate *InPlaceUpdateStatus `json:"inPlaceUpdate,omitempty" description:"the last in-place update of the pod"`
+	Lifecycle     *PodLifecycle        `json:"lifecycle,omitempty" description:"lifecycle state of the pod, if the cloneset has lifecycle hooks"`
+}
+
+// InPlaceUpdateStatus is the last in-place update of a pod, read from the state kruise records on it.
+type InPlaceUpdateStatus struct {
+	State                  string            `json:"state" description:"Updating or Updated"`
+	Revision               string            `json:"revision" description:"revision the pod was updated to"`
+	UpdateTimestamp        v1.Time           `json:"updateTimestamp" description:"time the in-place update started"`
+	PendingContainerImages map[string]string `json:"pendingContainerImages,omitempty" description:"images of containers updated in a later batch"`
+}
+
+// GetCloneSetStatus returns the rollout status of the CloneSet with the revision of each of its pods.
+func (c *operator) GetCloneSetStatus(namespace, name string) (*CloneSetRolloutStatus, error) {
+	cloneSet, err := c.kruiseclientset.AppsV1alpha1().CloneSets(namespace).Get(context.Background(), name, v1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	pods, err := c.ListPods(namespace, constants.CloneSetType, name)
+	if err != nil {
+		return nil, err
+	}
+
+	owned := make([]*corev1.Pod, 0, len(pods.Items))
+	for _, item := range pods.Items {
+		pod := item.(*corev1.Pod)
+		if isOwnedBy(pod.ObjectMeta, cloneSet.UID) {
+			owned = append(owned, pod)
+		}
+	}
+	return cloneSetRolloutStatus(cloneSet, owned), nil
+}
+
+func cloneSetRolloutStatus(cloneSet *v1alpha1.CloneSet, pods []*corev1.Pod) *CloneSetRolloutStatus {
+	replicas := cloneSetReplicas(cloneSet)
+	partition := 0
+	if cloneSet.Spec.UpdateStrategy.Partition != nil {
+		partition, _ = intstr.GetScaledValueFromIntOrPercent(cloneSet.Spec.UpdateStrategy.Partition, int(replicas), true)
+		if partition > int(replicas) {
+			partition = int(replicas)
+		}
+	}
+
+	status := &CloneSetRolloutStatus{
+		CloneSetRollout:         *cloneSetRollout(cloneSet),
+		AvailableReplicas:       cloneSet.Status.AvailableReplicas,
+		ExpectedUpdatedReplicas: cloneSet.Status.ExpectedUpdatedReplicas,
+		PartitionReplicas:       int32(partition),
+		Generation:              cloneSet.Generation,
+		ObservedGeneration:      cloneSet.Status.ObservedGeneration,
+		Conditions:              cloneSet.Status.Conditions,
+		Pods:                    make([]PodRevision, 0, len(pods)),
+	}
+	if lag := cloneSet.Generation - cloneSet.Status.ObservedGeneration; lag > 0 {
+		status.GenerationLag = lag
+	}
+
+	switch {
+	case status.GenerationLag > 0:
+		status.Phase = RolloutProgressing
+	case status.Paused:
+		status.Phase = RolloutPaused
+	case status.UpdatedReadyReplicas >= replicas && status.CurrentRevision == status.UpdateRevision:
+		status.Phase = RolloutCompleted
+	case status.UpdatedReadyReplicas >= replicas-status.PartitionReplicas:
+		status.Phase = RolloutWaiting
+	default:
+		status.Phase = RolloutProgressing
+	}
+
+	for _, pod := range pods {
+		podRevision := PodRevision{
+			Name:          pod.Name,
+			Revision:      pod.Labels[appsv1.ControllerRevisionHashLabelKey],
+			Updated:       IsPodAtRevision(pod, status.UpdateRevision),
+			Ready:         podConditionStatus(pod, corev1.PodReady) == corev1.ConditionTrue,
+			Phase:         pod.Status.Phase,
+			InPlaceUpdate: inPlaceUpdateStatus(pod),
+		}
+		if cloneSet.Spec.Lifecycle != nil {
+			podRevision.Lifecycle = podLifecycle(pod, cloneSet.Spec.Lifecycle)
+		}
+		status.Pods = append(status.Pods, podRevision)
+	}
+	sort.Slice(status.Pods, func(i, j int) bool {
+		return status.Pods[i].Name < status.Pods[j].Name
+	})
+	return status
+}
+
+// inPlaceUpdateStatus reads the in-place update state kruise records on the pod, nil if the pod was
+// never updated in place.
+func inPlaceUpdateStatus(pod *corev1.Pod) *InPlaceUpdateStatus {
+	stateData, ok := appspub.GetInPlaceUpdateState(pod)
+	if !ok {
+		return nil
+	}
+	state := appspub.InPlaceUpdateState{}
+	if err := json.Unmarshal([]byte(stateData), &state); err != nil {
+		klog.Warningf("invalid in-place update state of pod %s/%s: %v", pod.Namespace, pod.Name, err)
+		return nil
+	}
+
+	status := &InPlaceUpdateStatus{
+		State:                  InPlaceUpdated,
+		Revision:               state.Revision,
+		UpdateTimestamp:        state.UpdateTimestamp,
+		PendingContainerImages: state.NextContainerImages,
+	}
+	if len(state.NextContainerImages) > 0 || podConditionStatus(pod, appspub.InPlaceUpdateReady) == corev1.ConditionFalse {
+		status.State = InPlaceUpdating
+	}
+	return status
+}
+
+func podConditionStatus(pod *corev1.Pod, conditionType corev1.PodConditionType) corev1.ConditionStatus {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == conditionType {
+			return condition.Status
+		}
+	}
+	return corev1.ConditionUnknown
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	appspub "github.com/openkruise/kruise-api/apps/pub"
+	"github.com/openkruise/kruise-api/apps/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestCloneSetRolloutStatus(t *testing.T) {
+	replicas := int32(4)
+	partition := intstr.FromString("50%")
+	cloneSet := &v1alpha1.CloneSet{
+		ObjectMeta: v1.ObjectMeta{Name: "web", Namespace: "default", Generation: 3},
+		Spec: v1alpha1.CloneSetSpec{
+			Replicas:       &replicas,
+			UpdateStrategy: v1alpha1.CloneSetUpdateStrategy{Partition: &partition},
+		},
+		Status: v1alpha1.CloneSetStatus{
+			ObservedGeneration:   3,
+			Replicas:             4,
+			ReadyReplicas:        3,
+			UpdatedReplicas:      2,
+			UpdatedReadyReplicas: 1,
+			CurrentRevision:      "web-v1",
+			UpdateRevision:       "web-v2",
+		},
+	}
+	pod := func(name, revision string, ready corev1.ConditionStatus) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: v1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{appsv1.ControllerRevisionHashLabelKey: revision},
+			},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}},
+			},
+		}
+	}
+	updating := pod("web-b", "web-v2", corev1.ConditionFalse)
+	updating.Annotations = map[string]string{appspub.InPlaceUpdateStateKey: `{"revision":"web-v2","updateTimestamp":"2023-06-01T12:00:00Z"}`}
+	updating.Status.Conditions = append(updating.Status.Conditions, corev1.PodCondition{Type: appspub.InPlaceUpdateReady, Status: corev1.ConditionFalse})
+
+	status := cloneSetRolloutStatus(cloneSet, []*corev1.Pod{
+		pod("web-d", "web-v1", corev1.ConditionTrue),
+		updating,
+		pod("web-a", "v2", corev1.ConditionTrue),
+		pod("web-c", "web-v1", corev1.ConditionTrue),
+	})
+
+	assert.Equal(t, RolloutProgressing, status.Phase)
+	assert.Equal(t, int32(2), status.PartitionReplicas)
+	assert.Equal(t, int64(0), status.GenerationLag)
+	assert.Equal(t, "web-v2", status.UpdateRevision)
+	names := make([]string, 0, len(status.Pods))
+	for _, pod := range status.Pods {
+		names = append(names, pod.Name)
+	}
+	assert.Equal(t, []string{"web-a", "web-b", "web-c", "web-d"}, names)
+	assert.Equal(t, PodRevision{Name: "web-a", Revision: "v2", Updated: true, Ready: true, Phase: corev1.PodRunning}, status.Pods[0])
+	assert.False(t, status.Pods[1].Ready)
+	if assert.NotNil(t, status.Pods[1].InPlaceUpdate) {
+		assert.Equal(t, InPlaceUpdating, status.Pods[1].InPlaceUpdate.State)
+		assert.Equal(t, "web-v2", status.Pods[1].InPlaceUpdate.Revision)
+	}
+	assert.False(t, status.Pods[2].Updated)
+
+	cloneSet.Status.UpdatedReadyReplicas = 2
+	assert.Equal(t, RolloutWaiting, cloneSetRolloutStatus(cloneSet, nil).Phase)
+	cloneSet.Spec.UpdateStrategy.Paused = true
+	assert.Equal(t, RolloutPaused, cloneSetRolloutStatus(cloneSet, nil).Phase)
+	cloneSet.Generation = 4
+	status = cloneSetRolloutStatus(cloneSet, nil)
+	assert.Equal(t, RolloutProgressing, status.Phase)
+	assert.Equal(t, int64(1), status.GenerationLag)
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/api"
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/constants"
+	"github.com/openkruise/kruise-api/apps/v1alpha1"
+	"github.com/openkruise/kruise-api/apps/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// SubsetReplicas is the replica breakdown of a single UnitedDeployment subset.
+type SubsetReplicas struct {
+	Name            string `json:"name" description:"name of the subset"`
+	APIVersion      string `json:"apiVersion,omitempty" description:"api version of the underlying workload, apps.kruise.io/v1beta1 for an Advanced StatefulSet"`
+	Kind            string `json:"kind,omitempty" description:"kind of the underlying workload"`
+	Workload        string `json:"workload,omitempty" description:"name of the underlying workload"`
+	Replicas        int32  `json:"replicas" description:"desired replicas of the subset"`
+	ReadyReplicas   int32  `json:"readyReplicas" description:"ready replicas of the underlying workload"`
+	UpdatedReplicas int32  `json:"updatedReplicas" description:"updated replicas of the underlying workload"`
+	Partition       *int32 `json:"partition,omitempty" description:"current partition of the subset"`
+}
+
+// ListUnitedDeploymentSubsets reports desired, ready and updated replicas of every subset
+// in the UnitedDeployment topology, together with the workload backing it.
+func (c *operator) ListUnitedDeploymentSubsets(namespace, name string) (*api.ListResult, error) {
+	obj, err := c.resourceGetter.Get(constants.UnitedDeploymentType, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	unitedDeployment := obj.(*v1alpha1.UnitedDeployment)
+
+	workloads, err := c.listSubsetWorkloads(unitedDeployment)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]interface{}, 0, len(unitedDeployment.Spec.Topology.Subsets))
+	for _, replicas := range subsetReplicas(unitedDeployment, workloads) {
+		items = append(items, replicas)
+	}
+	return api.NewListResult(items, len(items)), nil
+}
+
+// subsetReplicas breaks the replicas of the UnitedDeployment down by subset, in topology order.
+func subsetReplicas(unitedDeployment *v1alpha1.UnitedDeployment, workloads map[string]SubsetReplicas) []SubsetReplicas {
+	subsets := make([]SubsetReplicas, 0, len(unitedDeployment.Spec.Topology.Subsets))
+	for _, subset := range unitedDeployment.Spec.Topology.Subsets {
+		replicas := SubsetReplicas{
+			Name:     subset.Name,
+			Replicas: unitedDeployment.Status.SubsetReplicas[subset.Name],
+		}
+		if workload, ok := workloads[subset.Name]; ok {
+			replicas.APIVersion = workload.APIVersion
+			replicas.Kind = workload.Kind
+			replicas.Workload = workload.Workload
+			replicas.ReadyReplicas = workload.ReadyReplicas
+			replicas.UpdatedReplicas = workload.UpdatedReplicas
+		}
+		if updateStatus := unitedDeployment.Status.UpdateStatus; updateStatus != nil {
+			if partition, ok := updateStatus.CurrentPartitions[subset.Name]; ok {
+				replicas.Partition = &partition
+			}
+		}
+		subsets = append(subsets, replicas)
+	}
+	return subsets
+}
+
+// listSubsetWorkloads returns the workloads owned by the UnitedDeployment keyed by subset name.
+func (c *operator) listSubsetWorkloads(unitedDeployment *v1alpha1.UnitedDeployment) (map[string]SubsetReplicas, error) {
+	namespace := unitedDeployment.Namespace
+	listOptions := v1.ListOptions{LabelSelector: v1alpha1.SubSetNameLabelKey}
+	workloads := make(map[string]SubsetReplicas)
+
+	add := func(meta v1.ObjectMeta, gvk schema.GroupVersionKind, ready, updated int32) {
+		if !isOwnedBy(meta, unitedDeployment.UID) {
+			return
+		}
+		apiVersion, kind := gvk.ToAPIVersionAndKind()
+		workloads[meta.Labels[v1alpha1.SubSetNameLabelKey]] = SubsetReplicas{
+			APIVersion:      apiVersion,
+			Kind:            kind,
+			Workload:        meta.Name,
+			ReadyReplicas:   ready,
+			UpdatedReplicas: updated,
+		}
+	}
+
+	template := unitedDeployment.Spec.Template
+	switch {
+	case template.CloneSetTemplate != nil:
+		list, err := c.kruiseclientset.AppsV1alpha1().CloneSets(namespace).List(context.Background(), listOptions)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range list.Items {
+			add(item.ObjectMeta, v1alpha1.SchemeGroupVersion.WithKind(constants.CloneSetTag), item.Status.ReadyReplicas, item.Status.UpdatedReplicas)
+		}
+	case template.AdvancedStatefulSetTemplate != nil:
+		list, err := c.kruiseclientset.AppsV1beta1().StatefulSets(namespace).List(context.Background(), listOptions)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range list.Items {
+			add(item.ObjectMeta, v1beta1.SchemeGroupVersion.WithKind(constants.StatefulSetTag), item.Status.ReadyReplicas, item.Status.UpdatedReplicas)
+		}
+	case template.StatefulSetTemplate != nil:
+		list, err := c.kubernetesclientset.AppsV1().StatefulSets(namespace).List(context.Background(), listOptions)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range list.Items {
+			add(item.ObjectMeta, appsv1.SchemeGroupVersion.WithKind(constants.StatefulSetTag), item.Status.ReadyReplicas, item.Status.UpdatedReplicas)
+		}
+	case template.DeploymentTemplate != nil:
+		list, err := c.kubernetesclientset.AppsV1().Deployments(namespace).List(context.Background(), listOptions)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range list.Items {
+			add(item.ObjectMeta, appsv1.SchemeGroupVersion.WithKind(constants.DeploymentTag), item.Status.ReadyReplicas, item.Status.UpdatedReplicas)
+		}
+	}
+	return workloads, nil
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/openkruise/kruise-api/apps/v1alpha1"
+	"github.com/openkruise/kruise-api/apps/v1beta1"
+	kruisefake "github.com/openkruise/kruise-api/client/clientset/versioned/fake"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestSubsetReplicas(t *testing.T) {
+	const uid = types.UID("ud-uid")
+	meta := func(name, subset string, owner types.UID) v1.ObjectMeta {
+		meta := v1.ObjectMeta{Name: name, Namespace: "default", OwnerReferences: []v1.OwnerReference{{UID: owner}}}
+		if subset != "" {
+			meta.Labels = map[string]string{v1alpha1.SubSetNameLabelKey: subset}
+		}
+		return meta
+	}
+	cloneSet := func(name, subset string, owner types.UID, ready, updated int32) runtime.Object {
+		return &v1alpha1.CloneSet{
+			ObjectMeta: meta(name, subset, owner),
+			Status:     v1alpha1.CloneSetStatus{ReadyReplicas: ready, UpdatedReplicas: updated},
+		}
+	}
+	advancedStatefulSet := func(name, subset string, owner types.UID, ready, updated int32) runtime.Object {
+		return &v1beta1.StatefulSet{
+			ObjectMeta: meta(name, subset, owner),
+			Status:     v1beta1.StatefulSetStatus{ReadyReplicas: ready, UpdatedReplicas: updated},
+		}
+	}
+	statefulSet := func(name, subset string, owner types.UID, ready, updated int32) runtime.Object {
+		return &appsv1.StatefulSet{
+			ObjectMeta: meta(name, subset, owner),
+			Status:     appsv1.StatefulSetStatus{ReadyReplicas: ready, UpdatedReplicas: updated},
+		}
+	}
+	partition := int32(1)
+
+	tests := []struct {
+		name          string
+		template      v1alpha1.SubsetTemplate
+		kruiseObjects []runtime.Object
+		objects       []runtime.Object
+		want          []SubsetReplicas
+	}{
+		{
+			name:     "cloneset subsets",
+			template: v1alpha1.SubsetTemplate{CloneSetTemplate: &v1alpha1.CloneSetTemplateSpec{}},
+			kruiseObjects: []runtime.Object{
+				cloneSet("ud-zone-a", "zone-a", uid, 3, 2),
+				cloneSet("ud-zone-b", "zone-b", uid, 1, 2),
+				cloneSet("other-zone-c", "zone-c", "other-uid", 1, 1),
+				cloneSet("unlabeled", "", uid, 1, 1),
+				// the workloads of the other templates are ignored
+				advancedStatefulSet("ud-zone-c", "zone-c", uid, 1, 1),
+			},
+			objects: []runtime.Object{statefulSet("native-zone-c", "zone-c", uid, 1, 1)},
+			want: []SubsetReplicas{
+				{Name: "zone-a", APIVersion: "apps.kruise.io/v1alpha1", Kind: "CloneSet", Workload: "ud-zone-a", Replicas: 3, ReadyReplicas: 3, UpdatedReplicas: 2, Partition: &partition},
+				{Name: "zone-b", APIVersion: "apps.kruise.io/v1alpha1", Kind: "CloneSet", Workload: "ud-zone-b", Replicas: 2, ReadyReplicas: 1, UpdatedReplicas: 2},
+				{Name: "zone-c", Replicas: 1},
+			},
+		},
+		{
+			name:     "advanced statefulset subsets",
+			template: v1alpha1.SubsetTemplate{AdvancedStatefulSetTemplate: &v1alpha1.AdvancedStatefulSetTemplateSpec{}},
+			kruiseObjects: []runtime.Object{
+				advancedStatefulSet("ud-zone-a", "zone-a", uid, 3, 3),
+				advancedStatefulSet("ud-zone-c", "zone-c", uid, 0, 1),
+				cloneSet("ud-zone-b", "zone-b", uid, 2, 2),
+			},
+			objects: []runtime.Object{statefulSet("native-zone-b", "zone-b", uid, 2, 2)},
+			want: []SubsetReplicas{
+				{Name: "zone-a", APIVersion: "apps.kruise.io/v1beta1", Kind: "StatefulSet", Workload: "ud-zone-a", Replicas: 3, ReadyReplicas: 3, UpdatedReplicas: 3, Partition: &partition},
+				{Name: "zone-b", Replicas: 2},
+				{Name: "zone-c", APIVersion: "apps.kruise.io/v1beta1", Kind: "StatefulSet", Workload: "ud-zone-c", Replicas: 1, ReadyReplicas: 0, UpdatedReplicas: 1},
+			},
+		},
+		{
+			name:          "native statefulset subsets",
+			template:      v1alpha1.SubsetTemplate{StatefulSetTemplate: &v1alpha1.StatefulSetTemplateSpec{}},
+			kruiseObjects: []runtime.Object{advancedStatefulSet("ud-zone-a", "zone-a", uid, 3, 3)},
+			objects: []runtime.Object{
+				statefulSet("native-zone-a", "zone-a", uid, 2, 1),
+				statefulSet("native-zone-b", "zone-b", uid, 2, 2),
+				statefulSet("other-zone-c", "zone-c", "other-uid", 1, 1),
+			},
+			want: []SubsetReplicas{
+				{Name: "zone-a", APIVersion: "apps/v1", Kind: "StatefulSet", Workload: "native-zone-a", Replicas: 3, ReadyReplicas: 2, UpdatedReplicas: 1, Partition: &partition},
+				{Name: "zone-b", APIVersion: "apps/v1", Kind: "StatefulSet", Workload: "native-zone-b", Replicas: 2, ReadyReplicas: 2, UpdatedReplicas: 2},
+				{Name: "zone-c", Replicas: 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unitedDeployment := &v1alpha1.UnitedDeployment{
+				ObjectMeta: v1.ObjectMeta{Name: "ud", Namespace: "default", UID: uid},
+				Spec: v1alpha1.UnitedDeploymentSpec{
+					Template: tt.template,
+					Topology: v1alpha1.Topology{Subsets: []v1alpha1.Subset{{Name: "zone-a"}, {Name: "zone-b"}, {Name: "zone-c"}}},
+				},
+				Status: v1alpha1.UnitedDeploymentStatus{
+					SubsetReplicas: map[string]int32{"zone-a": 3, "zone-b": 2, "zone-c": 1},
+					UpdateStatus:   &v1alpha1.UpdateStatus{CurrentPartitions: map[string]int32{"zone-a": partition}},
+				},
+			}
+			c := &operator{
+				kubernetesclientset: fake.NewSimpleClientset(tt.objects...),
+				kruiseclientset:     kruisefake.NewSimpleClientset(tt.kruiseObjects...),
+			}
+			workloads, err := c.listSubsetWorkloads(unitedDeployment)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, subsetReplicas(unitedDeployment, workloads))
+		})
+	}
+}
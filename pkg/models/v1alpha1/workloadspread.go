@@ -0,0 +1,157 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/constants"
+	"github.com/openkruise/kruise-api/apps/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// MatchedWorkloadSpreadAnnotationKey is set by the kruise webhook on every pod injected by a WorkloadSpread.
+const MatchedWorkloadSpreadAnnotationKey = "apps.kruise.io/matched-workloadspread"
+
+type injectWorkloadSpread struct {
+	Name   string `json:"name"`
+	Subset string `json:"subset"`
+}
+
+// CloneSetSpread shows how the pods of a CloneSet are distributed over the subsets
+// of the WorkloadSpread targeting it.
+type CloneSetSpread struct {
+	WorkloadSpread string         `json:"workloadSpread" description:"name of the workloadspread targeting the cloneset"`
+	Replicas       int32          `json:"replicas" description:"desired replicas of the cloneset"`
+	Subsets        []SpreadSubset `json:"subsets" description:"distribution of the pods over the subsets"`
+	UnmatchedPods  []string       `json:"unmatchedPods" description:"pods not injected into any subset of the workloadspread"`
+}
+
+type SpreadSubset struct {
+	Name             string              `json:"name" description:"name of the subset"`
+	MaxReplicas      *intstr.IntOrString `json:"maxReplicas,omitempty" description:"configured maxReplicas, unlimited if empty"`
+	MaxReplicasCount *int32              `json:"maxReplicasCount,omitempty" description:"maxReplicas resolved against the cloneset replicas"`
+	Replicas         int32               `json:"replicas" description:"number of pods currently in the subset"`
+	MissingReplicas  int32               `json:"missingReplicas" description:"missing replicas reported by the workloadspread, -1 means unlimited"`
+	Pods             []string            `json:"pods" description:"names of the pods in the subset"`
+}
+
+// GetCloneSetSpread finds the WorkloadSpread that applies to the CloneSet and compares the
+// actual pod distribution with the maxReplicas of each subset.
+func (c *operator) GetCloneSetSpread(namespace, name string) (*CloneSetSpread, error) {
+	obj, err := c.resourceGetter.Get(constants.CloneSetType, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	cloneSet := obj.(*v1alpha1.CloneSet)
+
+	workloadSpreads, err := c.workloadSpreadLister.WorkloadSpreads(namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	workloadSpread := findWorkloadSpread(workloadSpreads, v1alpha1.SchemeGroupVersion.WithKind(constants.CloneSetTag), name)
+	if workloadSpread == nil {
+		return nil, errors.NewNotFound(v1alpha1.Resource(constants.WorkloadSpreadType), name)
+	}
+
+	pods, err := c.ListPods(namespace, constants.CloneSetType, name)
+	if err != nil {
+		return nil, err
+	}
+	return cloneSetSpread(cloneSet, workloadSpread, pods.Items)
+}
+
+// cloneSetSpread distributes the pods of the CloneSet over the subsets of the WorkloadSpread by the
+// subset the kruise webhook injected them into.
+func cloneSetSpread(cloneSet *v1alpha1.CloneSet, workloadSpread *v1alpha1.WorkloadSpread, pods []interface{}) (*CloneSetSpread, error) {
+	var replicas int32
+	if cloneSet.Spec.Replicas != nil {
+		replicas = *cloneSet.Spec.Replicas
+	}
+
+	spread := &CloneSetSpread{
+		WorkloadSpread: workloadSpread.Name,
+		Replicas:       replicas,
+		Subsets:        make([]SpreadSubset, 0, len(workloadSpread.Spec.Subsets)),
+		UnmatchedPods:  []string{},
+	}
+
+	missing := make(map[string]int32)
+	for _, status := range workloadSpread.Status.SubsetStatuses {
+		missing[status.Name] = status.MissingReplicas
+	}
+
+	index := make(map[string]int)
+	for i, subset := range workloadSpread.Spec.Subsets {
+		spreadSubset := SpreadSubset{
+			Name:            subset.Name,
+			MaxReplicas:     subset.MaxReplicas,
+			MissingReplicas: missing[subset.Name],
+			Pods:            []string{},
+		}
+		if subset.MaxReplicas != nil {
+			count, err := intstr.GetScaledValueFromIntOrPercent(subset.MaxReplicas, int(replicas), true)
+			if err != nil {
+				return nil, err
+			}
+			maxReplicas := int32(count)
+			spreadSubset.MaxReplicasCount = &maxReplicas
+		}
+		index[subset.Name] = i
+		spread.Subsets = append(spread.Subsets, spreadSubset)
+	}
+
+	for _, item := range pods {
+		pod := item.(*corev1.Pod)
+		injected := injectWorkloadSpread{}
+		if value, ok := pod.Annotations[MatchedWorkloadSpreadAnnotationKey]; ok {
+			_ = json.Unmarshal([]byte(value), &injected)
+		}
+		i, ok := index[injected.Subset]
+		if !ok || injected.Name != workloadSpread.Name {
+			spread.UnmatchedPods = append(spread.UnmatchedPods, pod.Name)
+			continue
+		}
+		spread.Subsets[i].Replicas++
+		spread.Subsets[i].Pods = append(spread.Subsets[i].Pods, pod.Name)
+	}
+
+	return spread, nil
+}
+
+// findWorkloadSpread returns the oldest WorkloadSpread targeting the workload, nil if there is none.
+// The target must be in the group of the workload, any version of it.
+func findWorkloadSpread(workloadSpreads []*v1alpha1.WorkloadSpread, gvk schema.GroupVersionKind, name string) *v1alpha1.WorkloadSpread {
+	var matched *v1alpha1.WorkloadSpread
+	for _, workloadSpread := range workloadSpreads {
+		target := workloadSpread.Spec.TargetReference
+		if target == nil || target.Kind != gvk.Kind || target.Name != name {
+			continue
+		}
+		if gv, err := schema.ParseGroupVersion(target.APIVersion); err != nil || gv.Group != gvk.Group {
+			continue
+		}
+		if matched == nil || workloadSpread.CreationTimestamp.Before(&matched.CreationTimestamp) {
+			matched = workloadSpread
+		}
+	}
+	return matched
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Gentleelephant/EnhancementWorkload/pkg/constants"
+	"github.com/openkruise/kruise-api/apps/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestCloneSetSpread(t *testing.T) {
+	replicas := int32(6)
+	cloneSet := &v1alpha1.CloneSet{
+		ObjectMeta: v1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       v1alpha1.CloneSetSpec{Replicas: &replicas},
+	}
+	maxReplicas := func(value intstr.IntOrString) *intstr.IntOrString {
+		return &value
+	}
+	workloadSpread := &v1alpha1.WorkloadSpread{
+		ObjectMeta: v1.ObjectMeta{Name: "spread", Namespace: "default"},
+		Spec: v1alpha1.WorkloadSpreadSpec{
+			Subsets: []v1alpha1.WorkloadSpreadSubset{
+				{Name: "zone-a", MaxReplicas: maxReplicas(intstr.FromInt(2))},
+				{Name: "zone-b", MaxReplicas: maxReplicas(intstr.FromString("50%"))},
+				{Name: "spot"},
+			},
+		},
+		Status: v1alpha1.WorkloadSpreadStatus{
+			SubsetStatuses: []v1alpha1.WorkloadSpreadSubsetStatus{
+				{Name: "zone-a", MissingReplicas: 0},
+				{Name: "zone-b", MissingReplicas: 2},
+				{Name: "spot", MissingReplicas: -1},
+			},
+		},
+	}
+	pod := func(name, annotation string) interface{} {
+		pod := &corev1.Pod{ObjectMeta: v1.ObjectMeta{Name: name}}
+		if annotation != "" {
+			pod.Annotations = map[string]string{MatchedWorkloadSpreadAnnotationKey: annotation}
+		}
+		return pod
+	}
+	injected := func(workloadSpread, subset string) string {
+		return fmt.Sprintf(`{"name":%q,"subset":%q}`, workloadSpread, subset)
+	}
+
+	spread, err := cloneSetSpread(cloneSet, workloadSpread, []interface{}{
+		pod("web-1", injected("spread", "zone-a")),
+		pod("web-2", injected("spread", "zone-a")),
+		pod("web-3", injected("spread", "zone-b")),
+		pod("web-4", injected("spread", "spot")),
+		pod("web-5", injected("other", "zone-a")),
+		pod("web-6", injected("spread", "zone-c")),
+		pod("web-7", "invalid"),
+		pod("web-8", ""),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "spread", spread.WorkloadSpread)
+	assert.Equal(t, int32(6), spread.Replicas)
+
+	two, three := int32(2), int32(3)
+	assert.Equal(t, []SpreadSubset{
+		{Name: "zone-a", MaxReplicas: maxReplicas(intstr.FromInt(2)), MaxReplicasCount: &two, Replicas: 2, MissingReplicas: 0, Pods: []string{"web-1", "web-2"}},
+		{Name: "zone-b", MaxReplicas: maxReplicas(intstr.FromString("50%")), MaxReplicasCount: &three, Replicas: 1, MissingReplicas: 2, Pods: []string{"web-3"}},
+		{Name: "spot", Replicas: 1, MissingReplicas: -1, Pods: []string{"web-4"}},
+	}, spread.Subsets)
+	assert.Equal(t, []string{"web-5", "web-6", "web-7", "web-8"}, spread.UnmatchedPods)
+}
+
+func TestCloneSetSpreadWithoutPods(t *testing.T) {
+	cloneSet := &v1alpha1.CloneSet{ObjectMeta: v1.ObjectMeta{Name: "web"}}
+	maxReplicas := intstr.FromString("30%")
+	workloadSpread := &v1alpha1.WorkloadSpread{
+		ObjectMeta: v1.ObjectMeta{Name: "spread"},
+		Spec: v1alpha1.WorkloadSpreadSpec{
+			Subsets: []v1alpha1.WorkloadSpreadSubset{{Name: "zone-a", MaxReplicas: &maxReplicas}},
+		},
+	}
+
+	spread, err := cloneSetSpread(cloneSet, workloadSpread, nil)
+	assert.NoError(t, err)
+	zero := int32(0)
+	assert.Equal(t, []SpreadSubset{
+		{Name: "zone-a", MaxReplicas: &maxReplicas, MaxReplicasCount: &zero, Pods: []string{}},
+	}, spread.Subsets)
+	assert.Equal(t, []string{}, spread.UnmatchedPods)
+}
+
+func TestFindWorkloadSpread(t *testing.T) {
+	now := time.Now()
+	workloadSpread := func(name, apiVersion, kind, target string, age time.Duration) *v1alpha1.WorkloadSpread {
+		return &v1alpha1.WorkloadSpread{
+			ObjectMeta: v1.ObjectMeta{Name: name, CreationTimestamp: v1.NewTime(now.Add(-age))},
+			Spec: v1alpha1.WorkloadSpreadSpec{
+				TargetReference: &v1alpha1.TargetReference{APIVersion: apiVersion, Kind: kind, Name: target},
+			},
+		}
+	}
+	gvk := v1alpha1.SchemeGroupVersion.WithKind(constants.CloneSetTag)
+	tests := []struct {
+		name            string
+		workloadSpreads []*v1alpha1.WorkloadSpread
+		want            string
+	}{
+		{
+			name: "targets the cloneset",
+			workloadSpreads: []*v1alpha1.WorkloadSpread{
+				workloadSpread("spread", "apps.kruise.io/v1alpha1", constants.CloneSetTag, "web", time.Hour),
+			},
+			want: "spread",
+		},
+		{
+			name: "oldest of several",
+			workloadSpreads: []*v1alpha1.WorkloadSpread{
+				workloadSpread("newer", "apps.kruise.io/v1alpha1", constants.CloneSetTag, "web", time.Minute),
+				workloadSpread("older", "apps.kruise.io/v1alpha1", constants.CloneSetTag, "web", time.Hour),
+			},
+			want: "older",
+		},
+		{
+			name: "other version of the group",
+			workloadSpreads: []*v1alpha1.WorkloadSpread{
+				workloadSpread("spread", "apps.kruise.io/v1beta1", constants.CloneSetTag, "web", time.Hour),
+			},
+			want: "spread",
+		},
+		{
+			name: "same kind and name in another group",
+			workloadSpreads: []*v1alpha1.WorkloadSpread{
+				workloadSpread("spread", "example.com/v1", constants.CloneSetTag, "web", time.Hour),
+			},
+		},
+		{
+			name: "core group",
+			workloadSpreads: []*v1alpha1.WorkloadSpread{
+				workloadSpread("spread", "v1", constants.CloneSetTag, "web", time.Hour),
+			},
+		},
+		{
+			name: "invalid apiVersion",
+			workloadSpreads: []*v1alpha1.WorkloadSpread{
+				workloadSpread("spread", "apps.kruise.io/v1/alpha", constants.CloneSetTag, "web", time.Hour),
+			},
+		},
+		{
+			name: "other kind",
+			workloadSpreads: []*v1alpha1.WorkloadSpread{
+				workloadSpread("spread", "apps/v1", constants.DeploymentTag, "web", time.Hour),
+			},
+		},
+		{
+			name: "other cloneset",
+			workloadSpreads: []*v1alpha1.WorkloadSpread{
+				workloadSpread("spread", "apps.kruise.io/v1alpha1", constants.CloneSetTag, "api", time.Hour),
+			},
+		},
+		{
+			name:            "without target reference",
+			workloadSpreads: []*v1alpha1.WorkloadSpread{{ObjectMeta: v1.ObjectMeta{Name: "spread"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matched := findWorkloadSpread(tt.workloadSpreads, gvk, "web")
+			if tt.want == "" {
+				assert.Nil(t, matched)
+				return
+			}
+			if assert.NotNil(t, matched) {
+				assert.Equal(t, tt.want, matched.Name)
+			}
+		})
+	}
+}